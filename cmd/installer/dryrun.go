@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Change kinds recorded in a dry-run manifest.
+const (
+	ChangeDirectory = "directory"
+	ChangeFile      = "file"
+	ChangeShortcut  = "shortcut"
+	ChangeRegistry  = "registry"
+)
+
+// Change is one system modification the installer would make.
+type Change struct {
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+	Source string `json:"source,omitempty"`
+	Value  string `json:"value,omitempty"`
+	Note   string `json:"note,omitempty"`
+}
+
+// Manifest lists the changes an installation would make, in order.
+type Manifest struct {
+	InstallDir string   `json:"install_dir"`
+	Changes    []Change `json:"changes"`
+}
+
+// DryRunInstaller records intended changes instead of applying them. It only reads
+// the filesystem, to report whether an existing config.yaml would be kept.
+type DryRunInstaller struct {
+	// StartMenuDir and DesktopDir are the shortcut folders that would be used.
+	StartMenuDir string
+	DesktopDir   string
+
+	mu       sync.Mutex
+	manifest Manifest
+}
+
+// NewDryRunInstaller returns a DryRunInstaller using the given shortcut folders.
+func NewDryRunInstaller(startMenuDir, desktopDir string) *DryRunInstaller {
+	return &DryRunInstaller{StartMenuDir: startMenuDir, DesktopDir: desktopDir}
+}
+
+// Manifest returns a copy of the changes recorded so far.
+func (d *DryRunInstaller) Manifest() Manifest {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := d.manifest
+	out.Changes = append([]Change(nil), d.manifest.Changes...)
+	return out
+}
+
+func (d *DryRunInstaller) record(c Change) {
+	d.mu.Lock()
+	d.manifest.Changes = append(d.manifest.Changes, c)
+	d.mu.Unlock()
+}
+
+// PrepareInstallDir records creation of the installation directory.
+func (d *DryRunInstaller) PrepareInstallDir(config *InstallConfig) error {
+	if config.InstallDir == "" {
+		return fmt.Errorf("install directory not specified")
+	}
+	d.mu.Lock()
+	d.manifest.InstallDir = config.InstallDir
+	d.mu.Unlock()
+	d.record(Change{Kind: ChangeDirectory, Target: config.InstallDir})
+	return nil
+}
+
+// CopyFiles records the bundle files that would be extracted.
+func (d *DryRunInstaller) CopyFiles(config *InstallConfig, progress ProgressCallback) error {
+	if config.InstallDir == "" {
+		return fmt.Errorf("install directory not specified")
+	}
+	for i, file := range bundleFiles {
+		if progress != nil {
+			progress((i*100)/len(bundleFiles), fmt.Sprintf("Copying %s...", file.dst))
+		}
+		d.record(Change{Kind: ChangeFile, Target: filepath.Join(config.InstallDir, file.dst), Source: file.src})
+	}
+	configPath := filepath.Join(config.InstallDir, "config.yaml")
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		d.record(Change{Kind: ChangeFile, Target: configPath, Source: "config.example.yaml", Note: "default configuration"})
+	} else {
+		d.record(Change{Kind: ChangeFile, Target: configPath, Note: "existing configuration kept"})
+	}
+	if progress != nil {
+		progress(100, "All files copied successfully")
+	}
+	return nil
+}
+
+// CreateShortcuts records the shortcuts that would be created.
+func (d *DryRunInstaller) CreateShortcuts(config *InstallConfig) error {
+	if config.InstallDir == "" {
+		return fmt.Errorf("install directory not specified")
+	}
+	for _, s := range shortcutsFor(config, d.StartMenuDir, d.DesktopDir) {
+		d.record(Change{Kind: ChangeShortcut, Target: s.Path, Value: s.Target})
+	}
+	return nil
+}
+
+// RegisterAutostart records the autostart registry value.
+func (d *DryRunInstaller) RegisterAutostart(config *InstallConfig) error {
+	if config.InstallDir == "" {
+		return fmt.Errorf("install directory not specified")
+	}
+	d.record(Change{Kind: ChangeRegistry, Target: `HKCU\` + autostartKeyPath + `\` + appName, Value: autostartValue(config)})
+	return nil
+}
+
+// RegisterUninstall records the Add/Remove Programs registry values.
+func (d *DryRunInstaller) RegisterUninstall(config *InstallConfig) error {
+	if config.InstallDir == "" {
+		return fmt.Errorf("install directory not specified")
+	}
+	strs := uninstallStringValues(config)
+	names := make([]string, 0, len(strs))
+	for name := range strs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		d.record(Change{Kind: ChangeRegistry, Target: `HKCU\` + uninstallKeyPath + `\` + name, Value: strs[name]})
+	}
+	dwords := uninstallDWordValues()
+	names = names[:0]
+	for name := range dwords {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		d.record(Change{Kind: ChangeRegistry, Target: `HKCU\` + uninstallKeyPath + `\` + name, Value: fmt.Sprint(dwords[name])})
+	}
+	return nil
+}
+
+// writeManifest writes m as indented JSON to path, or to stdout when path is empty.
+func writeManifest(m Manifest, path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDryRunInstallProducesManifestWithoutChanges(t *testing.T) {
+	root := t.TempDir()
+	config := &InstallConfig{
+		InstallDir:            filepath.Join(root, "ProxyPilot"),
+		CreateDesktopShortcut: true,
+		EnableAutostart:       true,
+	}
+	inst := NewDryRunInstaller(filepath.Join(root, "StartMenu"), filepath.Join(root, "Desktop"))
+
+	var lastProgress int
+	if err := performInstall(inst, config, func(progress int, _ string) { lastProgress = progress }); err != nil {
+		t.Fatalf("performInstall: %v", err)
+	}
+	if lastProgress != 100 {
+		t.Fatalf("final progress = %d, want 100", lastProgress)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("read temp dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("dry run touched the filesystem: %v", entries)
+	}
+
+	m := inst.Manifest()
+	if m.InstallDir != config.InstallDir {
+		t.Fatalf("manifest install dir = %q, want %q", m.InstallDir, config.InstallDir)
+	}
+	counts := map[string]int{}
+	targets := map[string]string{}
+	for _, c := range m.Changes {
+		counts[c.Kind]++
+		targets[c.Target] = c.Value
+	}
+	if counts[ChangeDirectory] != 1 || counts[ChangeFile] != len(bundleFiles)+1 || counts[ChangeShortcut] != 2 {
+		t.Fatalf("unexpected change counts: %v", counts)
+	}
+	autostartKey := `HKCU\` + autostartKeyPath + `\` + appName
+	if v, ok := targets[autostartKey]; !ok || !strings.Contains(v, "ProxyPilot.exe") {
+		t.Fatalf("autostart change missing or wrong: %q", v)
+	}
+	if v := targets[`HKCU\`+uninstallKeyPath+`\InstallLocation`]; v != config.InstallDir {
+		t.Fatalf("InstallLocation = %q, want %q", v, config.InstallDir)
+	}
+}
+
+func TestDryRunSkipsOptionalSteps(t *testing.T) {
+	root := t.TempDir()
+	config := &InstallConfig{InstallDir: filepath.Join(root, "ProxyPilot")}
+	inst := NewDryRunInstaller(filepath.Join(root, "StartMenu"), filepath.Join(root, "Desktop"))
+	if err := performInstall(inst, config, nil); err != nil {
+		t.Fatalf("performInstall: %v", err)
+	}
+	for _, c := range inst.Manifest().Changes {
+		if strings.HasPrefix(c.Target, filepath.Join(root, "Desktop")) {
+			t.Fatalf("desktop shortcut recorded without CreateDesktopShortcut: %+v", c)
+		}
+		if strings.Contains(c.Target, autostartKeyPath) {
+			t.Fatalf("autostart recorded without EnableAutostart: %+v", c)
+		}
+	}
+}
+
+func TestDryRunRequiresInstallDir(t *testing.T) {
+	if err := performInstall(NewDryRunInstaller("", ""), &InstallConfig{}, nil); err == nil {
+		t.Fatal("expected error for empty install directory")
+	}
+}
@@ -10,32 +10,31 @@ import (
 	"path/filepath"
 )
 
-// InstallConfig holds the configuration for the installation process.
-type InstallConfig struct {
-	// InstallDir is the target installation directory.
-	// Defaults to %LOCALAPPDATA%\ProxyPilot.
-	InstallDir string
+// systemInstaller applies installation changes to the local system.
+type systemInstaller struct{}
 
-	// CreateDesktopShortcut indicates whether to create a Desktop shortcut.
-	CreateDesktopShortcut bool
+// PrepareInstallDir creates the installation directory.
+func (systemInstaller) PrepareInstallDir(config *InstallConfig) error {
+	if config.InstallDir == "" {
+		return fmt.Errorf("install directory not specified")
+	}
+	return os.MkdirAll(config.InstallDir, 0755)
+}
+
+func (systemInstaller) CopyFiles(config *InstallConfig, progress ProgressCallback) error {
+	return CopyFiles(config, progress)
+}
+
+func (systemInstaller) CreateShortcuts(config *InstallConfig) error {
+	return CreateShortcuts(config)
+}
 
-	// EnableAutostart indicates whether to register the app for autostart.
-	EnableAutostart bool
+func (systemInstaller) RegisterAutostart(config *InstallConfig) error {
+	return RegisterAutostart(config)
 }
 
-// ProgressCallback is called during file copy operations to report progress.
-type ProgressCallback func(progress int, status string)
-
-// bundleFiles is the list of files to extract from the embedded bundle.
-var bundleFiles = []struct {
-	src  string // Path within embeddedBundle.
-	dst  string // Destination filename.
-	size int64  // Approximate size for progress calculation.
-}{
-	{"bundle/ProxyPilot.exe", "ProxyPilot.exe", 0},
-	{"bundle/config.example.yaml", "config.example.yaml", 0},
-	{"bundle/icon.ico", "icon.ico", 0},
-	{"bundle/icon.png", "icon.png", 0},
+func (systemInstaller) RegisterUninstall(config *InstallConfig) error {
+	return RegisterUninstall(config)
 }
 
 // CopyFiles extracts the embedded bundle files to the installation directory.
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+const (
+	appName = "ProxyPilot"
+
+	// Registry key paths.
+	autostartKeyPath = `Software\Microsoft\Windows\CurrentVersion\Run`
+	uninstallKeyPath = `Software\Microsoft\Windows\CurrentVersion\Uninstall\` + appName
+	uninstallKeyBase = `Software\Microsoft\Windows\CurrentVersion\Uninstall`
+
+	// Application metadata.
+	appPublisher = "ProxyPilot"
+	appVersion   = "1.0.0"
+
+	shortcutDescription = "ProxyPilot - AI Proxy Router"
+)
+
+// InstallConfig holds the configuration for the installation process.
+type InstallConfig struct {
+	// InstallDir is the target installation directory.
+	// Defaults to %LOCALAPPDATA%\ProxyPilot.
+	InstallDir string
+
+	// CreateDesktopShortcut indicates whether to create a Desktop shortcut.
+	CreateDesktopShortcut bool
+
+	// EnableAutostart indicates whether to register the app for autostart.
+	EnableAutostart bool
+}
+
+// ProgressCallback is called during file copy operations to report progress.
+type ProgressCallback func(progress int, status string)
+
+// Installer performs the system changes made by an installation. The Windows
+// implementation applies them; DryRunInstaller only records them in a manifest.
+type Installer interface {
+	PrepareInstallDir(config *InstallConfig) error
+	CopyFiles(config *InstallConfig, progress ProgressCallback) error
+	CreateShortcuts(config *InstallConfig) error
+	RegisterAutostart(config *InstallConfig) error
+	RegisterUninstall(config *InstallConfig) error
+}
+
+// bundleFiles is the list of files to extract from the embedded bundle.
+var bundleFiles = []struct {
+	src  string // Path within embeddedBundle.
+	dst  string // Destination filename.
+	size int64  // Approximate size for progress calculation.
+}{
+	{"bundle/ProxyPilot.exe", "ProxyPilot.exe", 0},
+	{"bundle/config.example.yaml", "config.example.yaml", 0},
+	{"bundle/icon.ico", "icon.ico", 0},
+	{"bundle/icon.png", "icon.png", 0},
+}
+
+// shortcut describes one .lnk file created by the installer.
+type shortcut struct {
+	Path        string
+	Target      string
+	WorkingDir  string
+	Icon        string
+	Description string
+}
+
+// shortcutsFor lists the shortcuts for config. Empty folder paths are skipped.
+func shortcutsFor(config *InstallConfig, startMenuDir, desktopDir string) []shortcut {
+	exePath := filepath.Join(config.InstallDir, "ProxyPilot.exe")
+	iconPath := filepath.Join(config.InstallDir, "icon.ico")
+	dirs := []string{startMenuDir}
+	if config.CreateDesktopShortcut {
+		dirs = append(dirs, desktopDir)
+	}
+	out := make([]shortcut, 0, len(dirs))
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		out = append(out, shortcut{
+			Path:        filepath.Join(dir, appName+".lnk"),
+			Target:      exePath,
+			WorkingDir:  config.InstallDir,
+			Icon:        iconPath,
+			Description: shortcutDescription,
+		})
+	}
+	return out
+}
+
+// autostartValue returns the Run key value that launches the installed app.
+func autostartValue(config *InstallConfig) string {
+	// Quoted path to handle spaces.
+	return `"` + filepath.Join(config.InstallDir, "ProxyPilot.exe") + `"`
+}
+
+// uninstallStringValues returns the string values written under the uninstall key.
+func uninstallStringValues(config *InstallConfig) map[string]string {
+	exePath := filepath.Join(config.InstallDir, "ProxyPilot.exe")
+	return map[string]string{
+		"DisplayName":          appName,
+		"DisplayVersion":       appVersion,
+		"Publisher":            appPublisher,
+		"InstallLocation":      config.InstallDir,
+		"DisplayIcon":          filepath.Join(config.InstallDir, "icon.ico"),
+		"UninstallString":      `"` + exePath + `" --uninstall`,
+		"QuietUninstallString": `"` + exePath + `" --uninstall --quiet`,
+	}
+}
+
+// uninstallDWordValues returns the DWORD values written under the uninstall key.
+func uninstallDWordValues() map[string]uint32 {
+	return map[string]uint32{
+		"NoModify": 1,
+		"NoRepair": 1,
+		// Estimated installed size in KB (~40 MB).
+		"EstimatedSize": 40 * 1024,
+	}
+}
+
+// performInstall runs the installation steps against inst, reporting progress.
+// Shortcut, autostart and uninstall registration failures are reported but not fatal.
+func performInstall(inst Installer, config *InstallConfig, report ProgressCallback) error {
+	if report == nil {
+		report = func(int, string) {}
+	}
+
+	// Step 1: Prepare installation directory (10%).
+	report(5, "Preparing installation directory...")
+	if err := inst.PrepareInstallDir(config); err != nil {
+		return fmt.Errorf("failed to create install directory: %w", err)
+	}
+	report(10, "Installation directory ready")
+
+	// Step 2: Copy files (10% - 60%).
+	report(15, "Copying application files...")
+	if err := inst.CopyFiles(config, func(progress int, status string) {
+		// Map 0-100 to 15-60.
+		report(15+(progress*45/100), status)
+	}); err != nil {
+		return fmt.Errorf("failed to copy files: %w", err)
+	}
+	report(60, "Files copied successfully")
+
+	// Step 3: Create shortcuts (60% - 80%).
+	report(65, "Creating shortcuts...")
+	if err := inst.CreateShortcuts(config); err != nil {
+		report(70, "Warning: Some shortcuts could not be created")
+	} else {
+		report(80, "Shortcuts created")
+	}
+
+	// Step 4: Register autostart if enabled (80% - 90%).
+	if config.EnableAutostart {
+		report(85, "Configuring autostart...")
+		if err := inst.RegisterAutostart(config); err != nil {
+			report(88, "Warning: Autostart could not be configured")
+		} else {
+			report(90, "Autostart configured")
+		}
+	} else {
+		report(90, "Skipping autostart configuration")
+	}
+
+	// Step 5: Register uninstaller (90% - 100%).
+	report(92, "Registering application...")
+	if err := inst.RegisterUninstall(config); err != nil {
+		report(95, "Warning: Uninstall registration incomplete")
+	} else {
+		report(98, "Application registered")
+	}
+
+	report(100, "Installation complete!")
+	return nil
+}
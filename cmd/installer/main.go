@@ -4,6 +4,7 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io/fs"
 	"net"
@@ -22,7 +23,6 @@ import (
 const (
 	windowWidth  = 500
 	windowHeight = 400
-	appName      = "ProxyPilot"
 )
 
 var (
@@ -34,6 +34,30 @@ var (
 )
 
 func main() {
+	dryRun := flag.Bool("dry-run", false, "Print the changes an installation would make without applying them")
+	manifestPath := flag.String("manifest", "", "Write the dry-run manifest to this file instead of stdout")
+	desktop := flag.Bool("desktop-shortcut", false, "Dry run: include the Desktop shortcut")
+	autostart := flag.Bool("autostart", true, "Dry run: include autostart registration")
+	flag.Parse()
+
+	if *dryRun {
+		config := &InstallConfig{
+			InstallDir:            getInstallDir(),
+			CreateDesktopShortcut: *desktop,
+			EnableAutostart:       *autostart,
+		}
+		inst := NewDryRunInstaller(getStartMenuPath(), getDesktopPath())
+		if err := performInstall(inst, config, nil); err != nil {
+			fmt.Fprintln(os.Stderr, "dry run failed:", err)
+			os.Exit(1)
+		}
+		if err := writeManifest(inst.Manifest(), *manifestPath); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to write manifest:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Lock this goroutine to an OS thread - required for Windows COM/GUI operations.
 	runtime.LockOSThread()
 
@@ -173,57 +197,9 @@ func runInstallation(w webview2.WebView, config *InstallConfig) error {
 	// Initialize install configuration with defaults.
 	config.InstallDir = getInstallDir()
 
-	// Step 1: Prepare installation directory (10%).
-	callSetProgress(w, 5, "Preparing installation directory...")
-	if err := os.MkdirAll(config.InstallDir, 0755); err != nil {
-		return fmt.Errorf("failed to create install directory: %w", err)
-	}
-	callSetProgress(w, 10, "Installation directory ready")
-
-	// Step 2: Copy files (10% - 60%).
-	callSetProgress(w, 15, "Copying application files...")
-	if err := CopyFiles(config, func(progress int, status string) {
-		// Map 0-100 to 15-60.
-		mappedProgress := 15 + (progress * 45 / 100)
-		callSetProgress(w, mappedProgress, status)
-	}); err != nil {
-		return fmt.Errorf("failed to copy files: %w", err)
-	}
-	callSetProgress(w, 60, "Files copied successfully")
-
-	// Step 3: Create shortcuts (60% - 80%).
-	callSetProgress(w, 65, "Creating shortcuts...")
-	if err := CreateShortcuts(config); err != nil {
-		// Non-fatal: log but continue.
-		callSetProgress(w, 70, "Warning: Some shortcuts could not be created")
-	} else {
-		callSetProgress(w, 80, "Shortcuts created")
-	}
-
-	// Step 4: Register autostart if enabled (80% - 90%).
-	if config.EnableAutostart {
-		callSetProgress(w, 85, "Configuring autostart...")
-		if err := RegisterAutostart(config); err != nil {
-			// Non-fatal: log but continue.
-			callSetProgress(w, 88, "Warning: Autostart could not be configured")
-		} else {
-			callSetProgress(w, 90, "Autostart configured")
-		}
-	} else {
-		callSetProgress(w, 90, "Skipping autostart configuration")
-	}
-
-	// Step 5: Register uninstaller (90% - 100%).
-	callSetProgress(w, 92, "Registering application...")
-	if err := RegisterUninstall(config); err != nil {
-		// Non-fatal: log but continue.
-		callSetProgress(w, 95, "Warning: Uninstall registration incomplete")
-	} else {
-		callSetProgress(w, 98, "Application registered")
-	}
-
-	callSetProgress(w, 100, "Installation complete!")
-	return nil
+	return performInstall(systemInstaller{}, config, func(percent int, status string) {
+		callSetProgress(w, percent, status)
+	})
 }
 
 // callSetProgress calls window.setProgress in the WebView.
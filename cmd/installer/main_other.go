@@ -0,0 +1,9 @@
+//go:build !windows
+
+package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("The ProxyPilot installer is currently supported on Windows only.")
+}
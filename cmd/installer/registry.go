@@ -4,38 +4,23 @@ package main
 
 import (
 	"fmt"
-	"path/filepath"
 
 	"golang.org/x/sys/windows/registry"
 )
 
-const (
-	// Registry key paths.
-	autostartKeyPath = `Software\Microsoft\Windows\CurrentVersion\Run`
-	uninstallKeyPath = `Software\Microsoft\Windows\CurrentVersion\Uninstall\` + appName
-	uninstallKeyBase = `Software\Microsoft\Windows\CurrentVersion\Uninstall`
-
-	// Application metadata.
-	appPublisher = "ProxyPilot"
-	appVersion   = "1.0.0"
-)
-
 // RegisterAutostart adds the application to the Windows autostart registry.
 func RegisterAutostart(config *InstallConfig) error {
 	if config.InstallDir == "" {
 		return fmt.Errorf("install directory not specified")
 	}
 
-	exePath := filepath.Join(config.InstallDir, "ProxyPilot.exe")
-
 	key, _, err := registry.CreateKey(registry.CURRENT_USER, autostartKeyPath, registry.SET_VALUE)
 	if err != nil {
 		return fmt.Errorf("failed to open autostart registry key: %w", err)
 	}
 	defer key.Close()
 
-	// Add the application with quoted path to handle spaces.
-	if err := key.SetStringValue(appName, `"`+exePath+`"`); err != nil {
+	if err := key.SetStringValue(appName, autostartValue(config)); err != nil {
 		return fmt.Errorf("failed to set autostart value: %w", err)
 	}
 
@@ -80,9 +65,6 @@ func RegisterUninstall(config *InstallConfig) error {
 		return fmt.Errorf("install directory not specified")
 	}
 
-	exePath := filepath.Join(config.InstallDir, "ProxyPilot.exe")
-	iconPath := filepath.Join(config.InstallDir, "icon.ico")
-
 	// Create the uninstall key.
 	key, _, err := registry.CreateKey(registry.CURRENT_USER, uninstallKeyPath, registry.SET_VALUE)
 	if err != nil {
@@ -90,36 +72,19 @@ func RegisterUninstall(config *InstallConfig) error {
 	}
 	defer key.Close()
 
-	// Set the required values.
-	values := map[string]string{
-		"DisplayName":          appName,
-		"DisplayVersion":       appVersion,
-		"Publisher":            appPublisher,
-		"InstallLocation":      config.InstallDir,
-		"DisplayIcon":          iconPath,
-		"UninstallString":      `"` + exePath + `" --uninstall`,
-		"QuietUninstallString": `"` + exePath + `" --uninstall --quiet`,
-	}
-
-	for name, value := range values {
+	for name, value := range uninstallStringValues(config) {
 		if err := key.SetStringValue(name, value); err != nil {
 			return fmt.Errorf("failed to set %s: %w", name, err)
 		}
 	}
-
-	// Set NoModify and NoRepair flags.
-	if err := key.SetDWordValue("NoModify", 1); err != nil {
-		return fmt.Errorf("failed to set NoModify: %w", err)
-	}
-	if err := key.SetDWordValue("NoRepair", 1); err != nil {
-		return fmt.Errorf("failed to set NoRepair: %w", err)
-	}
-
-	// Estimate the installed size in KB.
-	estimatedSize := uint32(40 * 1024) // ~40 MB estimate.
-	if err := key.SetDWordValue("EstimatedSize", estimatedSize); err != nil {
-		// Non-fatal.
-		_ = err
+	for name, value := range uninstallDWordValues() {
+		if err := key.SetDWordValue(name, value); err != nil {
+			// EstimatedSize is informational only.
+			if name == "EstimatedSize" {
+				continue
+			}
+			return fmt.Errorf("failed to set %s: %w", name, err)
+		}
 	}
 
 	return nil
@@ -98,26 +98,9 @@ func CreateShortcuts(config *InstallConfig) error {
 		return fmt.Errorf("install directory not specified")
 	}
 
-	exePath := filepath.Join(config.InstallDir, "ProxyPilot.exe")
-	iconPath := filepath.Join(config.InstallDir, "icon.ico")
-
-	// Create Start Menu shortcut.
-	startMenuPath := getStartMenuPath()
-	if startMenuPath != "" {
-		shortcutPath := filepath.Join(startMenuPath, appName+".lnk")
-		if err := createShortcut(shortcutPath, exePath, config.InstallDir, iconPath, "ProxyPilot - AI Proxy Router"); err != nil {
-			return fmt.Errorf("failed to create Start Menu shortcut: %w", err)
-		}
-	}
-
-	// Create Desktop shortcut if requested.
-	if config.CreateDesktopShortcut {
-		desktopPath := getDesktopPath()
-		if desktopPath != "" {
-			shortcutPath := filepath.Join(desktopPath, appName+".lnk")
-			if err := createShortcut(shortcutPath, exePath, config.InstallDir, iconPath, "ProxyPilot - AI Proxy Router"); err != nil {
-				return fmt.Errorf("failed to create Desktop shortcut: %w", err)
-			}
+	for _, s := range shortcutsFor(config, getStartMenuPath(), getDesktopPath()) {
+		if err := createShortcut(s.Path, s.Target, s.WorkingDir, s.Icon, s.Description); err != nil {
+			return fmt.Errorf("failed to create shortcut %s: %w", s.Path, err)
 		}
 	}
 
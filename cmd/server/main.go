@@ -102,6 +102,8 @@ func main() {
 	var setupKilo bool
 	var setupRooCode bool
 	var setupAll bool
+	var workspaceDir string
+	var workspaceNamespace string
 	var switchAgent string
 	var switchMode string
 	var projectID string
@@ -119,6 +121,8 @@ func main() {
 	var cleanupExpired bool
 	var removeAccount string
 	var refreshTokens string
+	var testAccounts bool
+	var testConcurrency int
 	var jsonOutput bool
 	var quietMode bool
 	var verboseMode bool
@@ -177,6 +181,8 @@ func main() {
 	flag.BoolVar(&setupKilo, "setup-kilo", false, "Configure Kilo Code CLI to use ProxyPilot")
 	flag.BoolVar(&setupRooCode, "setup-roocode", false, "Configure RooCode (VS Code) to use ProxyPilot")
 	flag.BoolVar(&setupAll, "setup-all", false, "Configure all detected CLI agents (with backup)")
+	flag.StringVar(&workspaceDir, "workspace", "", "With --setup-claude/--setup-codex: register this project directory as a memory workspace and send its header")
+	flag.StringVar(&workspaceNamespace, "workspace-namespace", "", "Semantic memory namespace for --workspace (default: directory name)")
 	flag.StringVar(&switchAgent, "switch", "", "Switch agent config mode (e.g., --switch claude)")
 	flag.StringVar(&switchMode, "mode", "", "Switch mode: proxy, native, or status (default: status)")
 	flag.StringVar(&projectID, "project_id", "", "Project ID (Gemini only, not required)")
@@ -194,6 +200,8 @@ func main() {
 	flag.BoolVar(&cleanupExpired, "cleanup-expired", false, "Remove expired tokens and exit")
 	flag.StringVar(&removeAccount, "remove-account", "", "Remove a specific account by name and exit")
 	flag.StringVar(&refreshTokens, "refresh", "", "Force token refresh (all, or email/id to refresh specific)")
+	flag.BoolVar(&testAccounts, "test-accounts", false, "Send a minimal request through each account of the running proxy and report the results")
+	flag.IntVar(&testConcurrency, "concurrency", 1, "Number of accounts tested at once (used with -test-accounts)")
 	flag.BoolVar(&jsonOutput, "json", false, "Output in JSON format (overrides --quiet)")
 	flag.BoolVar(&quietMode, "quiet", false, "Run in quiet mode (overrides --verbose)")
 	flag.BoolVar(&verboseMode, "verbose", false, "Run in verbose mode")
@@ -594,7 +602,7 @@ func main() {
 		} else if info.IsDir() {
 			log.Info("Cloud deploy mode: Config path is a directory; standing by for configuration")
 			configFileExists = false
-		} else if cfg.Port == 0 {
+		} else if cfg.Port == 0 && len(cfg.Listen) == 0 {
 			// LoadConfigOptional returns empty config when file is empty or invalid.
 			// Config file exists but is empty or invalid; treat as missing config
 			log.Info("Cloud deploy mode: Configuration file is empty or invalid; standing by for valid configuration")
@@ -732,6 +740,16 @@ func main() {
 			os.Exit(1)
 		}
 		return
+	} else if testAccounts {
+		mgmtKey := password
+		if mgmtKey == "" {
+			mgmtKey, _ = desktopctl.GetManagementPassword()
+		}
+		if err := cmd.TestAccounts(cfg, mgmtKey, testConcurrency, jsonOutput); err != nil {
+			log.Errorf("test-accounts failed: %v", err)
+			os.Exit(1)
+		}
+		return
 	} else if showUsage {
 		if err := cmd.ShowUsage(jsonOutput); err != nil {
 			log.Errorf("usage failed: %v", err)
@@ -801,8 +819,14 @@ func main() {
 		cmd.DoDetectAgents()
 	} else if setupClaude {
 		cmd.DoSetupClaude(cfg)
+		if workspaceDir != "" {
+			cmd.DoSetupClaudeWorkspace(cfg, cmd.WorkspaceOptions{Dir: workspaceDir, Namespace: workspaceNamespace})
+		}
 	} else if setupCodex {
 		cmd.DoSetupCodex(cfg)
+		if workspaceDir != "" {
+			cmd.DoSetupCodexWorkspace(cfg, cmd.WorkspaceOptions{Dir: workspaceDir, Namespace: workspaceNamespace})
+		}
 	} else if setupDroid {
 		cmd.DoSetupDroid(cfg)
 	} else if setupOpenCode {
@@ -1,80 +1,178 @@
+// Package agentdebug is an opt-in diagnostics channel for investigating provider behaviour.
+//
+// Records are kept in a bounded in-memory ring buffer and never written to disk; they can
+// be dumped and cleared through the management API. The channel is disabled by default,
+// in which case Log returns immediately without allocating.
 package agentdebug
 
 import (
 	"encoding/json"
-	"os"
-	"runtime"
+	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 )
 
-const defaultWindowsLogPath = `d:\code\ProxyPilot\.cursor\debug.log`
+const (
+	// DefaultMaxEntries is the ring buffer capacity used when none is configured.
+	DefaultMaxEntries = 256
+	// DefaultMaxEntryBytes caps the encoded size of a single entry's data payload.
+	DefaultMaxEntryBytes = 4096
 
-var seq uint64
+	maxMessageBytes = 1024
+)
 
-type entry struct {
-	ID           string         `json:"id,omitempty"`
-	Timestamp    int64          `json:"timestamp"`
+// Entry is a single diagnostics record.
+type Entry struct {
+	ID           string         `json:"id"`
+	Timestamp    time.Time      `json:"timestamp"`
+	HypothesisID string         `json:"hypothesis_id,omitempty"`
 	Location     string         `json:"location"`
 	Message      string         `json:"message"`
 	Data         map[string]any `json:"data,omitempty"`
-	SessionID    string         `json:"sessionId"`
-	RunID        string         `json:"runId"`
-	HypothesisID string         `json:"hypothesisId"`
+	Truncated    bool           `json:"truncated,omitempty"`
 }
 
-func Log(hypothesisID, location, message string, data map[string]any) {
-	logPath := os.Getenv("PROXYPILOT_AGENT_DEBUG_LOG_PATH")
-	if logPath == "" {
-		if runtime.GOOS != "windows" {
-			// Debug session is Windows-scoped; avoid hard-failing on other OS.
-			return
+// Stats describes the current buffer state.
+type Stats struct {
+	Enabled       bool   `json:"enabled"`
+	Capacity      int    `json:"capacity"`
+	MaxEntryBytes int    `json:"max_entry_bytes"`
+	Count         int    `json:"count"`
+	Dropped       uint64 `json:"dropped"`
+}
+
+var (
+	enabled atomic.Bool
+	seq     atomic.Uint64
+
+	mu            sync.Mutex
+	ring          []Entry
+	head          int
+	count         int
+	dropped       uint64
+	maxEntryBytes = DefaultMaxEntryBytes
+)
+
+func init() {
+	ring = make([]Entry, DefaultMaxEntries)
+}
+
+// Configure applies the channel settings. Shrinking the capacity keeps the newest entries.
+func Configure(on bool, maxEntries, entryBytes int) {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	if entryBytes <= 0 {
+		entryBytes = DefaultMaxEntryBytes
+	}
+	mu.Lock()
+	if maxEntries != len(ring) {
+		kept := snapshotLocked()
+		if len(kept) > maxEntries {
+			dropped += uint64(len(kept) - maxEntries)
+			kept = kept[len(kept)-maxEntries:]
 		}
-		logPath = defaultWindowsLogPath
+		ring = make([]Entry, maxEntries)
+		copy(ring, kept)
+		count = len(kept)
+		head = count % maxEntries
 	}
+	maxEntryBytes = entryBytes
+	mu.Unlock()
+	enabled.Store(on)
+}
 
-	runID := os.Getenv("PROXYPILOT_AGENT_DEBUG_RUN_ID")
-	if runID == "" {
-		runID = "pre-fix"
-	}
+// SetEnabled toggles the channel without changing its limits.
+func SetEnabled(on bool) {
+	enabled.Store(on)
+}
 
-	now := time.Now()
-	id := atomic.AddUint64(&seq, 1)
+// Enabled reports whether Log records entries.
+func Enabled() bool {
+	return enabled.Load()
+}
 
-	b, err := json.Marshal(entry{
-		ID:           "log_" + now.Format("20060102T150405.000") + "_" + fmtUint(id),
-		Timestamp:    now.UnixMilli(),
+// Log records a diagnostics entry when the channel is enabled.
+// Data larger than the configured per-entry limit is replaced by a size marker.
+func Log(hypothesisID, location, message string, data map[string]any) {
+	if !enabled.Load() {
+		return
+	}
+	now := time.Now()
+	e := Entry{
+		ID:           "dbg_" + strconv.FormatUint(seq.Add(1), 10),
+		Timestamp:    now,
+		HypothesisID: hypothesisID,
 		Location:     location,
 		Message:      message,
-		Data:         data,
-		SessionID:    "debug-session",
-		RunID:        runID,
-		HypothesisID: hypothesisID,
-	})
-	if err != nil {
-		return
+	}
+	if len(e.Message) > maxMessageBytes {
+		e.Message = e.Message[:maxMessageBytes]
+		e.Truncated = true
 	}
 
-	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-	if err != nil {
-		return
+	mu.Lock()
+	limit := maxEntryBytes
+	mu.Unlock()
+	if len(data) > 0 {
+		if raw, err := json.Marshal(data); err != nil {
+			e.Data = map[string]any{"error": "unserializable data"}
+			e.Truncated = true
+		} else if len(raw) > limit {
+			e.Data = map[string]any{"omitted_bytes": len(raw)}
+			e.Truncated = true
+		} else {
+			e.Data = data
+		}
 	}
-	_, _ = f.Write(append(b, '\n'))
-	_ = f.Close()
+
+	mu.Lock()
+	if count == len(ring) {
+		dropped++
+	} else {
+		count++
+	}
+	ring[head] = e
+	head = (head + 1) % len(ring)
+	mu.Unlock()
+}
+
+// Snapshot returns the buffered entries, oldest first.
+func Snapshot() []Entry {
+	mu.Lock()
+	defer mu.Unlock()
+	return snapshotLocked()
+}
+
+// Clear discards all buffered entries.
+func Clear() {
+	mu.Lock()
+	for i := range ring {
+		ring[i] = Entry{}
+	}
+	head, count, dropped = 0, 0, 0
+	mu.Unlock()
 }
 
-func fmtUint(v uint64) string {
-	// tiny, allocation-light formatting; no need for strconv import in hot paths.
-	const digits = "0123456789"
-	if v == 0 {
-		return "0"
+// CurrentStats returns the buffer state.
+func CurrentStats() Stats {
+	mu.Lock()
+	defer mu.Unlock()
+	return Stats{
+		Enabled:       enabled.Load(),
+		Capacity:      len(ring),
+		MaxEntryBytes: maxEntryBytes,
+		Count:         count,
+		Dropped:       dropped,
 	}
-	var buf [20]byte
-	i := len(buf)
-	for v > 0 {
-		i--
-		buf[i] = digits[v%10]
-		v /= 10
+}
+
+func snapshotLocked() []Entry {
+	out := make([]Entry, 0, count)
+	start := (head - count + len(ring)) % len(ring)
+	for i := 0; i < count; i++ {
+		out = append(out, ring[(start+i)%len(ring)])
 	}
-	return string(buf[i:])
+	return out
 }
@@ -0,0 +1,58 @@
+package agentdebug
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogDisabledByDefault(t *testing.T) {
+	Clear()
+	Log("h1", "test", "ignored", nil)
+	if got := len(Snapshot()); got != 0 {
+		t.Fatalf("expected no entries while disabled, got %d", got)
+	}
+}
+
+func TestRingBufferKeepsNewest(t *testing.T) {
+	Configure(true, 3, 0)
+	t.Cleanup(func() {
+		Configure(false, DefaultMaxEntries, DefaultMaxEntryBytes)
+		Clear()
+	})
+	Clear()
+
+	for _, msg := range []string{"a", "b", "c", "d", "e"} {
+		Log("", "test", msg, nil)
+	}
+	entries := Snapshot()
+	if len(entries) != 3 || entries[0].Message != "c" || entries[2].Message != "e" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if stats := CurrentStats(); stats.Dropped != 2 || stats.Count != 3 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	Configure(true, 2, 0)
+	entries = Snapshot()
+	if len(entries) != 2 || entries[0].Message != "d" {
+		t.Fatalf("shrinking should keep newest entries, got %+v", entries)
+	}
+}
+
+func TestLogOmitsOversizedData(t *testing.T) {
+	Configure(true, 4, 64)
+	t.Cleanup(func() {
+		Configure(false, DefaultMaxEntries, DefaultMaxEntryBytes)
+		Clear()
+	})
+	Clear()
+
+	Log("", "test", "big", map[string]any{"body": strings.Repeat("x", 200)})
+	entries := Snapshot()
+	if len(entries) != 1 || !entries[0].Truncated {
+		t.Fatalf("expected truncated entry, got %+v", entries)
+	}
+	if _, ok := entries[0].Data["omitted_bytes"]; !ok {
+		t.Fatalf("expected omitted_bytes marker, got %+v", entries[0].Data)
+	}
+}
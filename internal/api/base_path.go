@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+// normalizeBasePath returns prefix with a leading slash and no trailing slash,
+// or "" when prefix addresses the root.
+func normalizeBasePath(prefix string) string {
+	prefix = strings.Trim(strings.TrimSpace(prefix), "/")
+	if prefix == "" {
+		return ""
+	}
+	return "/" + prefix
+}
+
+// basePathHandler serves next for requests under prefix with the prefix removed,
+// and answers 404 for everything else.
+func basePathHandler(prefix string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, prefix)
+		if !ok || (rest != "" && rest[0] != '/') {
+			http.NotFound(w, r)
+			return
+		}
+		if rest == "" {
+			rest = "/"
+		}
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = rest
+		if r.URL.RawPath != "" {
+			if raw, okRaw := strings.CutPrefix(r.URL.RawPath, prefix); okRaw {
+				r2.URL.RawPath = raw
+			} else {
+				r2.URL.RawPath = ""
+			}
+		}
+		r2.RequestURI = r2.URL.RequestURI()
+		next.ServeHTTP(w, r2)
+	})
+}
@@ -0,0 +1,183 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/registry"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/auth"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/executor"
+	sdktranslator "github.com/router-for-me/CLIProxyAPI/v6/sdk/translator"
+)
+
+const (
+	accountProbePrompt         = "ping"
+	accountProbeDefaultTimeout = 60 * time.Second
+)
+
+// AccountProbeRequest selects which accounts to test and how.
+type AccountProbeRequest struct {
+	// Auth limits the test to accounts whose ID, label or email contains this text.
+	Auth string `json:"auth,omitempty"`
+	// Model is tried on every account that serves it; other accounts use their first model.
+	Model string `json:"model,omitempty"`
+	// Concurrency is how many accounts are tested at once. Defaults to 1.
+	Concurrency int `json:"concurrency,omitempty"`
+	// TimeoutSeconds bounds each test request. Defaults to 60.
+	TimeoutSeconds int `json:"timeout-seconds,omitempty"`
+}
+
+// AccountProbeResult is the outcome of testing one account.
+type AccountProbeResult struct {
+	AuthID    string `json:"auth_id"`
+	Provider  string `json:"provider"`
+	Label     string `json:"label,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Model     string `json:"model,omitempty"`
+	Models    int    `json:"models"`
+	OK        bool   `json:"ok"`
+	Skipped   bool   `json:"skipped,omitempty"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// PostAccountsTest sends a minimal generation through each enabled account and reports
+// success, latency and how many models the account serves.
+// POST /v0/management/accounts/test
+func (h *Handler) PostAccountsTest(c *gin.Context) {
+	var req AccountProbeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+			return
+		}
+	}
+	if h.authManager == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "auth manager unavailable"})
+		return
+	}
+	timeout := accountProbeDefaultTimeout
+	if req.TimeoutSeconds > 0 {
+		timeout = time.Duration(req.TimeoutSeconds) * time.Second
+	}
+	concurrency := max(req.Concurrency, 1)
+
+	auths := selectProbeAuths(h.authManager.List(), req.Auth)
+	results := make([]AccountProbeResult, len(auths))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, auth := range auths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, auth *coreauth.Auth) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = h.probeAccount(c.Request.Context(), auth, strings.TrimSpace(req.Model), timeout)
+		}(i, auth)
+	}
+	wg.Wait()
+
+	succeeded, failed := 0, 0
+	for _, r := range results {
+		switch {
+		case r.OK:
+			succeeded++
+		case !r.Skipped:
+			failed++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"total":     len(results),
+		"succeeded": succeeded,
+		"failed":    failed,
+		"results":   results,
+	})
+}
+
+// selectProbeAuths returns enabled accounts matching filter, sorted by provider and ID.
+func selectProbeAuths(auths []*coreauth.Auth, filter string) []*coreauth.Auth {
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	out := make([]*coreauth.Auth, 0, len(auths))
+	for _, auth := range auths {
+		if auth == nil || auth.ID == "" || auth.Disabled || auth.Status == coreauth.StatusDisabled {
+			continue
+		}
+		if filter != "" {
+			email := strings.ToLower(authEmail(auth))
+			if !strings.Contains(strings.ToLower(auth.ID), filter) && !strings.Contains(strings.ToLower(auth.Label), filter) && !strings.Contains(email, filter) {
+				continue
+			}
+		}
+		out = append(out, auth)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Provider != out[j].Provider {
+			return out[i].Provider < out[j].Provider
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+func (h *Handler) probeAccount(ctx context.Context, auth *coreauth.Auth, preferred string, timeout time.Duration) AccountProbeResult {
+	res := AccountProbeResult{AuthID: auth.ID, Provider: auth.Provider, Label: auth.Label, Email: authEmail(auth)}
+	models := registry.GetGlobalRegistry().GetModelsForClient(auth.ID)
+	res.Models = len(models)
+	res.Model = probeModelFor(models, preferred)
+	if res.Model == "" {
+		res.Skipped = true
+		res.Error = "no models registered for account"
+		return res
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	payload, _ := json.Marshal(map[string]any{
+		"model":      res.Model,
+		"messages":   []map[string]string{{"role": "user", "content": accountProbePrompt}},
+		"max_tokens": 16,
+		"stream":     false,
+	})
+	start := time.Now()
+	_, err := h.authManager.Execute(reqCtx, []string{auth.Provider}, cliproxyexecutor.Request{
+		Model:   res.Model,
+		Payload: payload,
+	}, cliproxyexecutor.Options{
+		OriginalRequest: payload,
+		SourceFormat:    sdktranslator.FromString("openai"),
+		Metadata: map[string]any{
+			cliproxyexecutor.PinnedAuthMetadataKey:     auth.ID,
+			cliproxyexecutor.RequestedModelMetadataKey: res.Model,
+		},
+	})
+	res.LatencyMs = time.Since(start).Milliseconds()
+	res.OK = err == nil
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// probeModelFor returns preferred when the account serves it, otherwise the first
+// generation model it serves.
+func probeModelFor(models []*registry.ModelInfo, preferred string) string {
+	first := ""
+	for _, m := range models {
+		if m == nil || m.ID == "" {
+			continue
+		}
+		if preferred != "" && strings.EqualFold(m.ID, preferred) {
+			return m.ID
+		}
+		if first == "" && !strings.Contains(strings.ToLower(m.ID), "embed") {
+			first = m.ID
+		}
+	}
+	return first
+}
@@ -0,0 +1,48 @@
+package management
+
+import (
+	"testing"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/registry"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/auth"
+)
+
+func TestProbeModelForPrefersRequestedModel(t *testing.T) {
+	models := []*registry.ModelInfo{{ID: "text-embedding-004"}, {ID: "gemini-2.5-flash"}, {ID: "gemini-2.5-pro"}}
+	if got := probeModelFor(models, "GEMINI-2.5-PRO"); got != "gemini-2.5-pro" {
+		t.Fatalf("probeModelFor with preferred = %q", got)
+	}
+	if got := probeModelFor(models, "claude-sonnet"); got != "gemini-2.5-flash" {
+		t.Fatalf("probeModelFor fallback = %q, want first generation model", got)
+	}
+	if got := probeModelFor(nil, ""); got != "" {
+		t.Fatalf("probeModelFor without models = %q", got)
+	}
+}
+
+func TestSelectProbeAuthsFiltersAndSorts(t *testing.T) {
+	auths := []*coreauth.Auth{
+		{ID: "codex-b.json", Provider: "codex"},
+		{ID: "claude-a.json", Provider: "claude", Metadata: map[string]any{"email": "Me@Example.com"}},
+		{ID: "claude-off.json", Provider: "claude", Disabled: true},
+		{ID: "codex-a.json", Provider: "codex", Label: "work"},
+	}
+	got := selectProbeAuths(auths, "")
+	if len(got) != 3 || got[0].ID != "claude-a.json" || got[1].ID != "codex-a.json" || got[2].ID != "codex-b.json" {
+		t.Fatalf("unexpected selection: %v", probeIDs(got))
+	}
+	if got = selectProbeAuths(auths, "example.com"); len(got) != 1 || got[0].ID != "claude-a.json" {
+		t.Fatalf("email filter selected %v", probeIDs(got))
+	}
+	if got = selectProbeAuths(auths, "WORK"); len(got) != 1 || got[0].ID != "codex-a.json" {
+		t.Fatalf("label filter selected %v", probeIDs(got))
+	}
+}
+
+func probeIDs(auths []*coreauth.Auth) []string {
+	out := make([]string, 0, len(auths))
+	for _, a := range auths {
+		out = append(out, a.ID)
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package management
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/agentdebug"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/config"
+)
+
+// GetAgentDebug dumps the diagnostics ring buffer, oldest entry first.
+// GET /v0/management/agent-debug
+func (h *Handler) GetAgentDebug(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"stats":   agentdebug.CurrentStats(),
+		"entries": agentdebug.Snapshot(),
+	})
+}
+
+// PutAgentDebug enables or disables the diagnostics channel and persists the choice to config.
+// PUT/PATCH /v0/management/agent-debug {"value": true}
+func (h *Handler) PutAgentDebug(c *gin.Context) {
+	var body struct {
+		Value *bool `json:"value"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.Value == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+	enabled := *body.Value
+	if h.mutateConfig(c, func(cfg *config.Config) { cfg.AgentDebug.Enabled = enabled }) {
+		agentdebug.SetEnabled(enabled)
+	}
+}
+
+// DeleteAgentDebug clears the diagnostics ring buffer.
+// DELETE /v0/management/agent-debug
+func (h *Handler) DeleteAgentDebug(c *gin.Context) {
+	agentdebug.Clear()
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
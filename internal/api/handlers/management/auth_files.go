@@ -29,6 +29,7 @@ import (
 	iflowauth "github.com/router-for-me/CLIProxyAPI/v6/internal/auth/iflow"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/auth/kimi"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/auth/qwen"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/filelock"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/interfaces"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/misc"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/registry"
@@ -982,15 +983,16 @@ func (h *Handler) commitUploadedAuthFile(ctx context.Context, path string, data
 	if errRead != nil && !os.IsNotExist(errRead) {
 		return fmt.Errorf("failed to read existing auth file: %w", errRead)
 	}
-	if err := os.WriteFile(path, data, 0o600); err != nil {
+	if err := filelock.WithLock(path, func() error { return os.WriteFile(path, data, 0o600) }); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 	if err := h.registerAuthFromFile(ctx, path, data); err != nil {
-		if hadPrevious {
-			_ = os.WriteFile(path, previousData, 0o600)
-		} else {
-			_ = os.Remove(path)
-		}
+		_ = filelock.WithLock(path, func() error {
+			if hadPrevious {
+				return os.WriteFile(path, previousData, 0o600)
+			}
+			return os.Remove(path)
+		})
 		return err
 	}
 	return nil
@@ -15,6 +15,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/buildinfo"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/filelock"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/integrations"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/usage"
 	sdkAuth "github.com/router-for-me/CLIProxyAPI/v6/sdk/auth"
@@ -412,6 +413,11 @@ func saveConfigAtomically(path string, cfg *config.Config) error {
 	if path == "" {
 		return fmt.Errorf("config file path not configured")
 	}
+	unlock, err := filelock.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	info, err := os.Stat(path)
 	if err != nil {
 		return err
@@ -0,0 +1,37 @@
+package management
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/inflight"
+)
+
+// GetInflight lists the requests currently executing, oldest first, with their model,
+// serving account, elapsed time and bytes streamed so far.
+// GET /v0/management/inflight
+func (h *Handler) GetInflight(c *gin.Context) {
+	requests := inflight.List()
+	c.JSON(http.StatusOK, gin.H{"requests": requests, "count": len(requests)})
+}
+
+// DeleteInflight cancels the executing request identified by the id query parameter
+// (or path segment).
+// DELETE /v0/management/inflight?id=<id>
+// DELETE /v0/management/inflight/:id
+func (h *Handler) DeleteInflight(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		id = strings.TrimSpace(c.Query("id"))
+	}
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing id"})
+		return
+	}
+	if !inflight.Cancel(id) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "request not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "cancelled", "id": id})
+}
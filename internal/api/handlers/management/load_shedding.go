@@ -0,0 +1,19 @@
+package management
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/loadshed"
+)
+
+// GetLoadShedding returns the latest resource sample, whether low-priority requests are
+// currently being shed, and how many have been refused.
+// GET /v0/management/load-shedding
+func (h *Handler) GetLoadShedding(c *gin.Context) {
+	resp := gin.H{"status": loadshed.CurrentStatus()}
+	if h.cfg != nil {
+		resp["config"] = h.cfg.LoadShedding
+	}
+	c.JSON(http.StatusOK, resp)
+}
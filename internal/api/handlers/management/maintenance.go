@@ -0,0 +1,15 @@
+package management
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/maintenance"
+)
+
+// GetMaintenanceWindows lists the configured per-account maintenance windows, whether each
+// is currently open and when it next starts.
+// GET /v0/management/maintenance-windows
+func (h *Handler) GetMaintenanceWindows(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"maintenance-windows": maintenance.CurrentStatus()})
+}
@@ -2,21 +2,14 @@ package management
 
 import (
 	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 
-	"github.com/router-for-me/CLIProxyAPI/v6/internal/util"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/memory"
 )
 
 func memoryBaseDir() string {
-	if v := strings.TrimSpace(os.Getenv("CLIPROXY_MEMORY_DIR")); v != "" {
-		return v
-	}
-	if w := util.WritablePath(); w != "" {
-		return filepath.Join(w, ".proxypilot", "memory")
-	}
-	return filepath.Join(".proxypilot", "memory")
+	return memory.DefaultBaseDir()
 }
 
 func memoryExportMaxBytes() int64 {
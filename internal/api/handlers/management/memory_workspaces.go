@@ -0,0 +1,72 @@
+package management
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/memory"
+)
+
+type memoryWorkspaceRequest struct {
+	Path        string `json:"path"`
+	Namespace   string `json:"namespace"`
+	Description string `json:"description"`
+}
+
+// ListMemoryWorkspaces returns the registered workspace → namespace mappings.
+// GET /v0/management/memory/workspaces
+func (h *Handler) ListMemoryWorkspaces(c *gin.Context) {
+	store := memory.NewFileStore(memoryBaseDir())
+	items, err := store.ListWorkspaces()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if items == nil {
+		items = []memory.Workspace{}
+	}
+	c.JSON(http.StatusOK, gin.H{"workspaces": items})
+}
+
+// PutMemoryWorkspace registers or updates a workspace.
+// PUT/POST /v0/management/memory/workspaces {"path": "...", "namespace": "...", "description": "..."}
+func (h *Handler) PutMemoryWorkspace(c *gin.Context) {
+	var req memoryWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Path) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+	store := memory.NewFileStore(memoryBaseDir())
+	ws, err := store.RegisterWorkspace(memory.Workspace{
+		Path:        req.Path,
+		Namespace:   req.Namespace,
+		Description: req.Description,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"workspace": ws})
+}
+
+// DeleteMemoryWorkspace removes a workspace by path or namespace. Stored memory is kept.
+// DELETE /v0/management/memory/workspaces?key=...
+func (h *Handler) DeleteMemoryWorkspace(c *gin.Context) {
+	key := strings.TrimSpace(c.Query("key"))
+	if key == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing key"})
+		return
+	}
+	store := memory.NewFileStore(memoryBaseDir())
+	removed, err := store.UnregisterWorkspace(key)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "workspace not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
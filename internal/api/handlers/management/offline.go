@@ -0,0 +1,32 @@
+package management
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/offline"
+)
+
+// GetOffline returns the current offline mode state, including auto-detection results.
+// GET /v0/management/offline
+func (h *Handler) GetOffline(c *gin.Context) {
+	c.JSON(http.StatusOK, offline.CurrentStatus())
+}
+
+// PutOffline forces offline mode on or off and persists the choice to config.
+// PUT/PATCH /v0/management/offline {"value": true}
+func (h *Handler) PutOffline(c *gin.Context) {
+	var body struct {
+		Value *bool `json:"value"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.Value == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid body"})
+		return
+	}
+	enabled := *body.Value
+	if h.mutateConfig(c, func(cfg *config.Config) { cfg.Offline.Enabled = enabled }) {
+		// Apply immediately instead of waiting for the config watcher to reload.
+		offline.SetForced(enabled)
+	}
+}
@@ -0,0 +1,44 @@
+package management
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/api/middleware"
+)
+
+// GetPinnedSessions lists sessions pinned to full history.
+// GET /v0/management/pinned-sessions
+func (h *Handler) GetPinnedSessions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"pinned-sessions": middleware.GetPinnedSessions()})
+}
+
+// PutPinnedSession pins a session so the prompt budget never trims its history.
+// PUT /v0/management/pinned-sessions
+func (h *Handler) PutPinnedSession(c *gin.Context) {
+	var body struct {
+		Session string `json:"session"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || strings.TrimSpace(body.Session) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "session is required"})
+		return
+	}
+	middleware.PinSession(body.Session)
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// DeletePinnedSession removes a session's pin.
+// DELETE /v0/management/pinned-sessions?session=<id>
+func (h *Handler) DeletePinnedSession(c *gin.Context) {
+	session := strings.TrimSpace(c.Query("session"))
+	if session == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "session is required"})
+		return
+	}
+	if !middleware.UnpinSession(session) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not pinned"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
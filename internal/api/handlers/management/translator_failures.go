@@ -0,0 +1,53 @@
+package management
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/translatordebug"
+)
+
+// GetTranslatorFailures reports translator failure counters and the quarantined samples.
+// GET /v0/management/translator-failures
+func (h *Handler) GetTranslatorFailures(c *gin.Context) {
+	samples, err := translatordebug.ListSamples()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	settings := translatordebug.CurrentSettings()
+	c.JSON(http.StatusOK, gin.H{
+		"quarantine":  settings.Quarantine,
+		"sample-rate": settings.SampleRate,
+		"counters":    translatordebug.Counters(),
+		"samples":     samples,
+	})
+}
+
+// GetTranslatorFailureSample returns a single quarantined sample.
+// GET /v0/management/translator-failures/samples/:name
+func (h *Handler) GetTranslatorFailureSample(c *gin.Context) {
+	sample, err := translatordebug.ReadSample(c.Param("name"))
+	if err != nil {
+		if errors.Is(err, translatordebug.ErrSampleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "sample not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sample)
+}
+
+// DeleteTranslatorFailures resets the counters and removes quarantined samples.
+// DELETE /v0/management/translator-failures
+func (h *Handler) DeleteTranslatorFailures(c *gin.Context) {
+	translatordebug.ResetCounters()
+	removed, err := translatordebug.ClearSamples()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "removed": removed})
+}
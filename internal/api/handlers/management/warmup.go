@@ -0,0 +1,19 @@
+package management
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/warmup"
+)
+
+// GetWarmup returns the configured warm-up settings and the outcome of the latest run,
+// including per-account results.
+// GET /v0/management/warmup
+func (h *Handler) GetWarmup(c *gin.Context) {
+	resp := gin.H{"status": warmup.CurrentStatus()}
+	if h.cfg != nil {
+		resp["config"] = h.cfg.Warmup
+	}
+	c.JSON(http.StatusOK, resp)
+}
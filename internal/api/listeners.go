@@ -0,0 +1,180 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// unixSocketMode restricts API sockets to the owning user and group.
+const unixSocketMode os.FileMode = 0o660
+
+// listenAll binds every configured address and merges them into one listener.
+// On failure, listeners opened so far are closed.
+func listenAll(addrs []string) (net.Listener, error) {
+	listeners := make([]net.Listener, 0, len(addrs))
+	closeAll := func() {
+		for _, ln := range listeners {
+			_ = ln.Close()
+		}
+	}
+	for _, entry := range addrs {
+		network, address, errParse := config.ParseListenAddress(entry)
+		if errParse != nil {
+			closeAll()
+			return nil, errParse
+		}
+		ln, errListen := listenOne(network, address)
+		if errListen != nil {
+			closeAll()
+			return nil, fmt.Errorf("listen %s: %w", entry, errListen)
+		}
+		listeners = append(listeners, ln)
+	}
+	if len(listeners) == 0 {
+		return nil, fmt.Errorf("no listen addresses configured")
+	}
+	if len(listeners) == 1 {
+		return listeners[0], nil
+	}
+	return newMultiListener(listeners), nil
+}
+
+func listenOne(network, address string) (net.Listener, error) {
+	if network != "unix" {
+		return net.Listen(network, address)
+	}
+	// A socket file left behind by an unclean shutdown blocks bind; remove it only if
+	// nothing is accepting on it.
+	if info, errStat := os.Stat(address); errStat == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, errDial := net.Dial("unix", address); errDial == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("socket %s is already in use", address)
+		}
+		if errRemove := os.Remove(address); errRemove != nil {
+			return nil, errRemove
+		}
+	}
+	ln, err := net.Listen("unix", address)
+	if err != nil {
+		return nil, err
+	}
+	if errChmod := os.Chmod(address, unixSocketMode); errChmod != nil {
+		log.Warnf("failed to restrict permissions on %s: %v", address, errChmod)
+	}
+	return &unixListener{Listener: ln}, nil
+}
+
+// unixListener reports Unix socket peers as loopback so localhost-only checks
+// (management access, amp restrictions) treat them as local clients.
+type unixListener struct {
+	net.Listener
+}
+
+func (l *unixListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &unixConn{Conn: conn}, nil
+}
+
+type unixConn struct {
+	net.Conn
+}
+
+func (c *unixConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}
+}
+
+// multiListener fans in connections accepted on several listeners.
+type multiListener struct {
+	listeners []net.Listener
+	conns     chan net.Conn
+	errs      chan error
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newMultiListener(listeners []net.Listener) *multiListener {
+	m := &multiListener{
+		listeners: listeners,
+		conns:     make(chan net.Conn),
+		errs:      make(chan error, len(listeners)),
+		done:      make(chan struct{}),
+	}
+	for _, ln := range listeners {
+		go m.serve(ln)
+	}
+	return m
+}
+
+func (m *multiListener) serve(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			select {
+			case m.errs <- err:
+			case <-m.done:
+			}
+			return
+		}
+		select {
+		case m.conns <- conn:
+		case <-m.done:
+			_ = conn.Close()
+			return
+		}
+	}
+}
+
+// Accept returns the next connection from any listener. An accept error on one
+// listener is returned once, which stops the server just like a single listener would.
+func (m *multiListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-m.conns:
+		return conn, nil
+	case err := <-m.errs:
+		return nil, err
+	case <-m.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes all underlying listeners.
+func (m *multiListener) Close() error {
+	var errs []error
+	m.closeOnce.Do(func() {
+		close(m.done)
+		for _, ln := range m.listeners {
+			if err := ln.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+				errs = append(errs, err)
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// Addr returns the address of the first listener.
+func (m *multiListener) Addr() net.Addr {
+	return m.listeners[0].Addr()
+}
+
+// describeListener renders the bound addresses for startup logs.
+func describeListener(ln net.Listener) string {
+	m, ok := ln.(*multiListener)
+	if !ok {
+		return ln.Addr().String()
+	}
+	parts := make([]string, 0, len(m.listeners))
+	for _, l := range m.listeners {
+		parts = append(parts, l.Addr().Network()+":"+l.Addr().String())
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListenAllServesTCPAndUnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pp-sock")
+	if err != nil {
+		t.Fatalf("mkdtemp: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	sock := filepath.Join(dir, "api.sock")
+
+	ln, err := listenAll([]string{"127.0.0.1:0", "unix:" + sock})
+	if err != nil {
+		t.Fatalf("listenAll: %v", err)
+	}
+	multi, ok := ln.(*multiListener)
+	if !ok {
+		t.Fatalf("listener type = %T, want *multiListener", ln)
+	}
+	if info, errStat := os.Stat(sock); errStat != nil || info.Mode().Perm() != unixSocketMode {
+		t.Fatalf("socket mode = %v, err %v", info, errStat)
+	}
+
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		_, _ = io.WriteString(w, host)
+	})}
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	resp, err := http.Get("http://" + multi.listeners[0].Addr().String() + "/")
+	if err != nil {
+		t.Fatalf("tcp GET: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	unixClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+		},
+	}}
+	resp, err = unixClient.Get("http://unix/")
+	if err != nil {
+		t.Fatalf("unix GET: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "127.0.0.1" {
+		t.Fatalf("unix peer reported as %q, want loopback", body)
+	}
+}
+
+func TestListenAllReplacesStaleSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "pp-sock")
+	if err != nil {
+		t.Fatalf("mkdtemp: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	sock := filepath.Join(dir, "api.sock")
+
+	first, err := listenAll([]string{sock})
+	if err != nil {
+		t.Fatalf("first listen: %v", err)
+	}
+	if _, err = listenAll([]string{sock}); err == nil {
+		t.Fatal("expected error while socket is in use")
+	}
+	// Simulate an unclean shutdown: close without unlinking the socket file.
+	first.(*unixListener).Listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = first.Close()
+
+	second, err := listenAll([]string{sock})
+	if err != nil {
+		t.Fatalf("listen over stale socket: %v", err)
+	}
+	_ = second.Close()
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/pacing"
+)
+
+// ClientPacingMiddleware reserves one request from the caller's pacing budget and reports
+// the remaining budget in OpenAI-compatible x-ratelimit-* headers. When enforcement is on
+// and the budget is exhausted the request is refused with 429. It must run after
+// authentication so budgets are tracked per client key; only POST requests are counted.
+func ClientPacingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodPost || !pacing.Enabled() {
+			c.Next()
+			return
+		}
+		snap, allowed := pacing.Reserve(pacing.ClientKey(c))
+		writePacingHeaders(c.Writer.Header(), snap)
+		if allowed {
+			c.Next()
+			return
+		}
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(snap.Reset.Seconds()))))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error": gin.H{
+				"message": "client rate limit reached; retry after the window resets",
+				"type":    "rate_limit_error",
+				"code":    "rate_limit_exceeded",
+			},
+		})
+	}
+}
+
+func writePacingHeaders(h http.Header, snap pacing.Snapshot) {
+	reset := formatPacingReset(snap.Reset)
+	if snap.LimitRequests > 0 {
+		h.Set("X-Ratelimit-Limit-Requests", strconv.Itoa(snap.LimitRequests))
+		h.Set("X-Ratelimit-Remaining-Requests", strconv.Itoa(snap.RemainingRequests))
+		h.Set("X-Ratelimit-Reset-Requests", reset)
+	}
+	if snap.LimitTokens > 0 {
+		h.Set("X-Ratelimit-Limit-Tokens", strconv.Itoa(snap.LimitTokens))
+		h.Set("X-Ratelimit-Remaining-Tokens", strconv.Itoa(snap.RemainingTokens))
+		h.Set("X-Ratelimit-Reset-Tokens", reset)
+	}
+}
+
+// formatPacingReset renders d the way OpenAI does, e.g. "1s" or "6m0s", rounded up to a second.
+func formatPacingReset(d time.Duration) string {
+	if d <= 0 {
+		return "0s"
+	}
+	return (d + time.Second - 1).Truncate(time.Second).String()
+}
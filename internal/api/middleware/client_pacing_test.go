@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/pacing"
+)
+
+func TestClientPacingMiddlewareAdvertisesAndEnforcesBudget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Cleanup(func() { pacing.Apply(pacing.Settings{}) })
+	pacing.Apply(pacing.Settings{Enabled: true, RequestsPerMinute: 1, TokensPerMinute: 1000, Enforce: true})
+
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) { c.Set("apiKey", "pacing-test-key") }, ClientPacingMiddleware())
+	engine.POST("/v1/chat/completions", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.GET("/v1/models", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("first request status = %d", rec.Code)
+	}
+	if got := rec.Header().Get("X-Ratelimit-Remaining-Requests"); got != "0" {
+		t.Fatalf("remaining requests = %q, want 0", got)
+	}
+	if got := rec.Header().Get("X-Ratelimit-Limit-Tokens"); got != "1000" {
+		t.Fatalf("token limit = %q, want 1000", got)
+	}
+	if got := rec.Header().Get("X-Ratelimit-Reset-Requests"); got != "1m0s" {
+		t.Fatalf("reset = %q, want 1m0s", got)
+	}
+
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("second request status = %d, retry-after %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	rec = httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/models", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("X-Ratelimit-Remaining-Requests") != "" {
+		t.Fatalf("GET should bypass pacing, status %d headers %v", rec.Code, rec.Header())
+	}
+}
@@ -3,7 +3,6 @@ package middleware
 import (
 	"net/http"
 	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -12,7 +11,6 @@ import (
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/embeddings"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/memory"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/registry"
-	"github.com/router-for-me/CLIProxyAPI/v6/internal/util"
 )
 
 const (
@@ -113,15 +111,7 @@ func agenticMemoryStore() memory.Store {
 			}
 		}
 
-		base := strings.TrimSpace(os.Getenv("CLIPROXY_MEMORY_DIR"))
-		if base == "" {
-			if w := util.WritablePath(); w != "" {
-				base = filepath.Join(w, ".proxypilot", "memory")
-			} else {
-				base = filepath.Join(".proxypilot", "memory")
-			}
-		}
-		memStore = memory.NewFileStore(base)
+		memStore = memory.NewFileStore(memory.DefaultBaseDir())
 
 		if agenticLLMSummaryEnabled() {
 			if fs, ok := memStore.(*memory.FileStore); ok {
@@ -121,6 +121,20 @@ func CodexPromptBudgetMiddlewareWithRootDir(rootDir string) gin.HandlerFunc {
 			}
 		}
 
+		// Sessions pinned to full history are never trimmed or rewritten; when they outgrow
+		// the requested model they move to a larger-context fallback model instead.
+		if session := extractAgenticSessionKey(req, body); session != "" {
+			applyPinHistoryHeader(c, session)
+			if IsSessionPinned(session) {
+				body = switchPinnedSessionModel(c, body, tokenAnalysis)
+				req.Body = io.NopCloser(bytes.NewReader(body))
+				req.ContentLength = int64(len(body))
+				req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+				c.Next()
+				return
+			}
+		}
+
 		// Session-scoped state (pinned + anchor + TODO + spec) is injected as append-only
 		// scaffolding when enabled. This preserves prompt-cache friendliness.
 		if agenticScaffoldEnabled() {
@@ -241,7 +255,7 @@ func agenticMaybeUpsertAndInjectPackedState(req *http.Request, session string, b
 	}
 	mem := ""
 	if agenticSemanticEnabled() && !fs.IsSemanticDisabled(session) {
-		ns := semanticNamespace(req, body, session)
+		ns := semanticNamespace(fs, req, body, session)
 		query := semanticQueryText(shape, body)
 		query = strings.TrimSpace(query)
 		if query != "" {
@@ -486,30 +500,46 @@ func appendSystemBlock(shape string, body []byte, block string, maxBytes int) ([
 	}
 }
 
-func semanticNamespace(req *http.Request, body []byte, session string) string {
+// workspaceHeader carries the registered workspace (namespace or path) a request belongs to.
+// Agent setup writes it into agent configs so retrieval boundaries do not depend on guessing.
+const workspaceHeader = "X-ProxyPilot-Workspace"
+
+// semanticNamespace picks the semantic memory namespace for a request. An explicit workspace
+// header wins; otherwise repo hints from headers or body are used. Either is mapped through the
+// registered workspaces so any path inside a workspace resolves to its namespace.
+func semanticNamespace(fs *memory.FileStore, req *http.Request, body []byte, session string) string {
 	if req != nil {
-		for _, h := range []string{"X-CLIProxyAPI-Repo", "X-Repo-Path", "X-Workspace-Root", "X-Project-Root"} {
-			if v := strings.TrimSpace(req.Header.Get(h)); v != "" {
-				return v
+		if v := strings.TrimSpace(req.Header.Get(workspaceHeader)); v != "" {
+			if ws, ok := fs.ResolveWorkspace(v); ok {
+				return ws.Namespace
 			}
+			return v
 		}
 	}
-	if v := strings.TrimSpace(gjson.GetBytes(body, "metadata.repo").String()); v != "" {
-		return v
+	hint := repoHint(req, body)
+	if hint == "" {
+		return session
 	}
-	if v := strings.TrimSpace(gjson.GetBytes(body, "metadata.repo_path").String()); v != "" {
-		return v
+	if ws, ok := fs.ResolveWorkspace(hint); ok {
+		return ws.Namespace
 	}
-	if v := strings.TrimSpace(gjson.GetBytes(body, "metadata.workspace_root").String()); v != "" {
-		return v
-	}
-	if v := strings.TrimSpace(gjson.GetBytes(body, "repo").String()); v != "" {
-		return v
+	return hint
+}
+
+func repoHint(req *http.Request, body []byte) string {
+	if req != nil {
+		for _, h := range []string{"X-CLIProxyAPI-Repo", "X-Repo-Path", "X-Workspace-Root", "X-Project-Root"} {
+			if v := strings.TrimSpace(req.Header.Get(h)); v != "" {
+				return v
+			}
+		}
 	}
-	if v := strings.TrimSpace(gjson.GetBytes(body, "workspace_root").String()); v != "" {
-		return v
+	for _, key := range []string{"metadata.repo", "metadata.repo_path", "metadata.workspace_root", "repo", "workspace_root"} {
+		if v := strings.TrimSpace(gjson.GetBytes(body, key).String()); v != "" {
+			return v
+		}
 	}
-	return session
+	return ""
 }
 
 func semanticQueryText(shape string, body []byte) string {
@@ -939,7 +969,7 @@ func agenticStoreAndInjectMemory(c *gin.Context, req *http.Request, session stri
 			}
 		}
 		if agenticSemanticEnabled() && len(res.Dropped) > 0 && !fs.IsSemanticDisabled(session) {
-			ns := semanticNamespace(req, res.Body, session)
+			ns := semanticNamespace(fs, req, res.Body, session)
 			if allowSemanticWrite(session) {
 				texts, roles := collectSemanticTexts(res.Dropped, 12)
 				if len(texts) > 0 {
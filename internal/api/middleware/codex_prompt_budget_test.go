@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/memory"
 	"github.com/stretchr/testify/require"
 	"github.com/tidwall/gjson"
 )
@@ -43,3 +44,20 @@ func TestAppendSystemBlockChat(t *testing.T) {
 	require.Equal(t, "system", msgs[1].Get("role").String())
 	require.Contains(t, msgs[1].Get("content").String(), "proxypilot_anchor")
 }
+
+func TestSemanticNamespaceUsesRegisteredWorkspace(t *testing.T) {
+	fs := memory.NewFileStore(t.TempDir())
+	_, err := fs.RegisterWorkspace(memory.Workspace{Path: "/src/app", Namespace: "app"})
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest(http.MethodPost, "/v1/messages", nil)
+	req.Header.Set("X-Repo-Path", "/src/app/cmd/server")
+	require.Equal(t, "app", semanticNamespace(fs, req, nil, "session-1"))
+
+	req.Header.Set(workspaceHeader, "pinned")
+	require.Equal(t, "pinned", semanticNamespace(fs, req, nil, "session-1"))
+
+	other, _ := http.NewRequest(http.MethodPost, "/v1/messages", nil)
+	require.Equal(t, "/elsewhere", semanticNamespace(fs, other, []byte(`{"metadata":{"repo":"/elsewhere"}}`), "session-1"))
+	require.Equal(t, "session-1", semanticNamespace(nil, other, nil, "session-1"))
+}
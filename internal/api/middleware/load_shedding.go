@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/loadshed"
+)
+
+// LoadSheddingMiddleware refuses low-priority requests with 503 and Retry-After while
+// resource usage is above the configured watermarks. Other requests pass through.
+func LoadSheddingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		retryAfter, reason, shed := loadshed.ShouldShed()
+		if !shed || !loadshed.IsLowPriority(c.Request.URL.Path, c.GetHeader(loadshed.PriorityHeader)) {
+			c.Next()
+			return
+		}
+		loadshed.RecordShed(c.Request.Method, c.Request.URL.Path, reason)
+		c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error": gin.H{
+				"message": "server is under resource pressure; low-priority request shed (" + reason + ")",
+				"type":    "overloaded_error",
+				"code":    "load_shed",
+			},
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/loadshed"
+)
+
+func TestLoadSheddingMiddlewareShedsLowPriorityOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Cleanup(loadshed.Stop)
+	loadshed.Apply(loadshed.Settings{
+		Enabled:            true,
+		MaxProcessMemoryMB: 1,
+		SampleInterval:     10 * time.Millisecond,
+		RetryAfter:         7 * time.Second,
+		LowPriorityPaths:   []string{"/embeddings"},
+	})
+	deadline := time.Now().Add(2 * time.Second)
+	for !loadshed.CurrentStatus().Overloaded {
+		if time.Now().After(deadline) {
+			t.Fatal("process memory watermark not reported as exceeded")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	engine := gin.New()
+	engine.Use(LoadSheddingMiddleware())
+	engine.POST("/*path", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	cases := []struct {
+		path, priority string
+		want           int
+	}{
+		{path: "/v1/embeddings", want: http.StatusServiceUnavailable},
+		{path: "/v1/chat/completions", want: http.StatusOK},
+		{path: "/v1/chat/completions", priority: "batch", want: http.StatusServiceUnavailable},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, tc.path, nil)
+		if tc.priority != "" {
+			req.Header.Set(loadshed.PriorityHeader, tc.priority)
+		}
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Fatalf("%s (%q) = %d, want %d", tc.path, tc.priority, rec.Code, tc.want)
+		}
+		if tc.want == http.StatusServiceUnavailable && rec.Header().Get("Retry-After") != "7" {
+			t.Fatalf("Retry-After = %q, want 7", rec.Header().Get("Retry-After"))
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/memory"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/sjson"
+)
+
+// PinHistoryHeader marks the request's session as pinned ("true") or unpinned ("false").
+// Pinned sessions keep their full history: the prompt budget middleware never trims them.
+const PinHistoryHeader = "X-ProxyPilot-Pin-History"
+
+// PinnedSession describes one session pinned to full history.
+type PinnedSession struct {
+	Session  string    `json:"session"`
+	PinnedAt time.Time `json:"pinned_at"`
+}
+
+var (
+	pinnedMu       sync.Mutex
+	pinnedLoaded   bool
+	pinnedSessions = map[string]time.Time{}
+	pinnedFallback []string
+
+	// pinnedSessionsFile returns where pins are persisted; replaced in tests.
+	pinnedSessionsFile = func() string {
+		return filepath.Join(memory.DefaultBaseDir(), "pinned-sessions.json")
+	}
+)
+
+// SetPinnedHistoryFallbackModels sets the models, in order of preference, that a pinned
+// session is switched to when its history no longer fits the requested model's context.
+func SetPinnedHistoryFallbackModels(models []string) {
+	cleaned := make([]string, 0, len(models))
+	for _, m := range models {
+		if m = strings.TrimSpace(m); m != "" {
+			cleaned = append(cleaned, m)
+		}
+	}
+	pinnedMu.Lock()
+	pinnedFallback = cleaned
+	pinnedMu.Unlock()
+}
+
+// PinSession marks session as pinned to full history and persists the change.
+func PinSession(session string) {
+	session = strings.TrimSpace(session)
+	if session == "" {
+		return
+	}
+	pinnedMu.Lock()
+	defer pinnedMu.Unlock()
+	loadPinnedSessionsLocked()
+	if _, ok := pinnedSessions[session]; ok {
+		return
+	}
+	pinnedSessions[session] = time.Now().UTC()
+	savePinnedSessionsLocked()
+}
+
+// UnpinSession removes the pin for session. It reports whether the session was pinned.
+func UnpinSession(session string) bool {
+	session = strings.TrimSpace(session)
+	pinnedMu.Lock()
+	defer pinnedMu.Unlock()
+	loadPinnedSessionsLocked()
+	if _, ok := pinnedSessions[session]; !ok {
+		return false
+	}
+	delete(pinnedSessions, session)
+	savePinnedSessionsLocked()
+	return true
+}
+
+// IsSessionPinned reports whether session is pinned to full history.
+func IsSessionPinned(session string) bool {
+	if session == "" {
+		return false
+	}
+	pinnedMu.Lock()
+	defer pinnedMu.Unlock()
+	loadPinnedSessionsLocked()
+	_, ok := pinnedSessions[session]
+	return ok
+}
+
+// GetPinnedSessions returns the pinned sessions sorted by pin time.
+func GetPinnedSessions() []PinnedSession {
+	pinnedMu.Lock()
+	defer pinnedMu.Unlock()
+	loadPinnedSessionsLocked()
+	out := make([]PinnedSession, 0, len(pinnedSessions))
+	for session, at := range pinnedSessions {
+		out = append(out, PinnedSession{Session: session, PinnedAt: at})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].PinnedAt.Equal(out[j].PinnedAt) {
+			return out[i].PinnedAt.Before(out[j].PinnedAt)
+		}
+		return out[i].Session < out[j].Session
+	})
+	return out
+}
+
+func loadPinnedSessionsLocked() {
+	if pinnedLoaded {
+		return
+	}
+	pinnedLoaded = true
+	data, err := os.ReadFile(pinnedSessionsFile())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warnf("failed to read pinned sessions: %v", err)
+		}
+		return
+	}
+	var stored []PinnedSession
+	if errUnmarshal := json.Unmarshal(data, &stored); errUnmarshal != nil {
+		log.Warnf("failed to parse pinned sessions: %v", errUnmarshal)
+		return
+	}
+	for _, p := range stored {
+		if p.Session != "" {
+			pinnedSessions[p.Session] = p.PinnedAt
+		}
+	}
+}
+
+func savePinnedSessionsLocked() {
+	stored := make([]PinnedSession, 0, len(pinnedSessions))
+	for session, at := range pinnedSessions {
+		stored = append(stored, PinnedSession{Session: session, PinnedAt: at})
+	}
+	sort.Slice(stored, func(i, j int) bool { return stored[i].Session < stored[j].Session })
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return
+	}
+	path := pinnedSessionsFile()
+	if errMkdir := os.MkdirAll(filepath.Dir(path), 0o755); errMkdir != nil {
+		log.Warnf("failed to persist pinned sessions: %v", errMkdir)
+		return
+	}
+	tmp := path + ".tmp"
+	if errWrite := os.WriteFile(tmp, data, 0o644); errWrite != nil {
+		log.Warnf("failed to persist pinned sessions: %v", errWrite)
+		return
+	}
+	if errRename := os.Rename(tmp, path); errRename != nil {
+		log.Warnf("failed to persist pinned sessions: %v", errRename)
+	}
+}
+
+// applyPinHistoryHeader pins or unpins session according to PinHistoryHeader and strips
+// the header so it is not forwarded upstream.
+func applyPinHistoryHeader(c *gin.Context, session string) {
+	v := strings.ToLower(strings.TrimSpace(c.Request.Header.Get(PinHistoryHeader)))
+	c.Request.Header.Del(PinHistoryHeader)
+	switch v {
+	case "":
+	case "1", "true", "on", "yes":
+		PinSession(session)
+	case "0", "false", "off", "no":
+		UnpinSession(session)
+	}
+}
+
+// pinnedSessionFallback returns the first configured fallback model with a larger context
+// window that fits the request, or "" when nothing larger is configured.
+func pinnedSessionFallback(analysis *tokenAwareCompressionResult, reserve int64) string {
+	pinnedMu.Lock()
+	fallback := append([]string(nil), pinnedFallback...)
+	pinnedMu.Unlock()
+	for _, model := range fallback {
+		if strings.EqualFold(model, analysis.Model) {
+			continue
+		}
+		window := getModelContextWindow(model)
+		if window > analysis.ContextWindow && analysis.CurrentTokens+reserve <= int64(window) {
+			return model
+		}
+	}
+	return ""
+}
+
+// switchPinnedSessionModel rewrites the request model when a pinned session has outgrown
+// the requested model's context. The body is returned unchanged when it still fits or no
+// fallback applies.
+func switchPinnedSessionModel(c *gin.Context, body []byte, analysis *tokenAwareCompressionResult) []byte {
+	if analysis == nil || analysis.Model == "" || analysis.ContextWindow <= 0 {
+		return body
+	}
+	reserve := int64(agenticReserveTokens())
+	if analysis.CurrentTokens+reserve <= int64(analysis.ContextWindow) {
+		return body
+	}
+	model := pinnedSessionFallback(analysis, reserve)
+	if model == "" {
+		log.Warnf("pinned session exceeds %s context (%d tokens) and no larger fallback model is configured", analysis.Model, analysis.CurrentTokens)
+		return body
+	}
+	updated, err := sjson.SetBytes(body, "model", model)
+	if err != nil {
+		return body
+	}
+	log.Infof("pinned session exceeds %s context (%d tokens); switching to %s", analysis.Model, analysis.CurrentTokens, model)
+	c.Header("X-ProxyPilot-Model-Fallback", model)
+	return updated
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func resetPinnedSessions(t *testing.T) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "pinned-sessions.json")
+	prevFile := pinnedSessionsFile
+	pinnedSessionsFile = func() string { return file }
+	reset := func() {
+		pinnedMu.Lock()
+		pinnedLoaded = false
+		pinnedSessions = map[string]time.Time{}
+		pinnedFallback = nil
+		pinnedMu.Unlock()
+	}
+	reset()
+	t.Cleanup(func() {
+		pinnedSessionsFile = prevFile
+		reset()
+	})
+}
+
+func TestPinnedSessionsPersist(t *testing.T) {
+	resetPinnedSessions(t)
+
+	PinSession("s1")
+	require.True(t, IsSessionPinned("s1"))
+
+	// Simulate a restart: pins are reloaded from disk.
+	pinnedMu.Lock()
+	pinnedLoaded = false
+	pinnedSessions = map[string]time.Time{}
+	pinnedMu.Unlock()
+	require.True(t, IsSessionPinned("s1"))
+	require.Len(t, GetPinnedSessions(), 1)
+
+	require.True(t, UnpinSession("s1"))
+	require.False(t, UnpinSession("s1"))
+	require.False(t, IsSessionPinned("s1"))
+}
+
+func TestPinnedSessionSkipsTrimAndSwitchesModel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resetPinnedSessions(t)
+	t.Setenv("CLIPROXY_SCAFFOLD_ENABLED", "false")
+	t.Setenv("CLIPROXY_MEMORY_ENABLED", "false")
+	SetPinnedHistoryFallbackModels([]string{"gpt-4", "gemini-test-large"})
+
+	var forwarded []byte
+	r := gin.New()
+	r.Use(CodexPromptBudgetMiddleware())
+	r.POST("/v1/chat/completions", func(c *gin.Context) {
+		forwarded, _ = io.ReadAll(c.Request.Body)
+		require.Empty(t, c.Request.Header.Get(PinHistoryHeader))
+		c.Status(http.StatusOK)
+	})
+
+	content := strings.Repeat("history ", 20000)
+	body := []byte(`{"model":"gpt-4","prompt_cache_key":"sess-1","messages":[` +
+		`{"role":"user","content":"` + content + `"},` +
+		`{"role":"assistant","content":"ok"},` +
+		`{"role":"user","content":"next"}]}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "claude-cli/1.0")
+	req.Header.Set(PinHistoryHeader, "true")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.True(t, IsSessionPinned("sess-1"))
+	require.Equal(t, "gemini-test-large", gjson.GetBytes(forwarded, "model").String())
+	require.Len(t, gjson.GetBytes(forwarded, "messages").Array(), 3)
+	require.Equal(t, content, gjson.GetBytes(forwarded, "messages.0.content").String())
+	require.Equal(t, "gemini-test-large", w.Header().Get("X-ProxyPilot-Model-Fallback"))
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/redisqueue"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/usage"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/util"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/warmup"
 	sdkaccess "github.com/router-for-me/CLIProxyAPI/v6/sdk/access"
 	"github.com/router-for-me/CLIProxyAPI/v6/sdk/api/handlers"
 	"github.com/router-for-me/CLIProxyAPI/v6/sdk/api/handlers/claude"
@@ -57,6 +58,9 @@ type serverOptionConfig struct {
 	keepAliveTimeout     time.Duration
 	keepAliveOnTimeout   func()
 	postAuthHook         auth.PostAuthHook
+	listener             net.Listener
+	skipDefaultMW        bool
+	basePath             string
 }
 
 // ServerOption customises HTTP server construction.
@@ -65,7 +69,11 @@ type ServerOption func(*serverOptionConfig)
 func defaultRequestLoggerFactory(cfg *config.Config, configPath string) logging.RequestLogger {
 	configDir := filepath.Dir(configPath)
 	logsDir := logging.ResolveLogDirectory(cfg)
-	return logging.NewFileRequestLogger(cfg.RequestLog, logsDir, configDir, cfg.ErrorLogsMaxFiles)
+	fileLogger := logging.NewFileRequestLogger(cfg.RequestLog, logsDir, configDir, cfg.ErrorLogsMaxFiles)
+	if cfg.RequestLogAsync {
+		return logging.NewAsyncRequestLogger(fileLogger, cfg.RequestLogBufferSize)
+	}
+	return fileLogger
 }
 
 // WithMiddleware appends additional Gin middleware during server construction.
@@ -122,6 +130,31 @@ func WithPostAuthHook(hook auth.PostAuthHook) ServerOption {
 	}
 }
 
+// WithListener serves on a pre-built listener (e.g. systemd socket activation or tests)
+// instead of listening on the configured host and port. TLS from config still applies.
+func WithListener(ln net.Listener) ServerOption {
+	return func(cfg *serverOptionConfig) {
+		cfg.listener = ln
+	}
+}
+
+// WithoutDefaultMiddleware skips the built-in Gin logging and panic recovery middleware,
+// for hosts that install their own.
+func WithoutDefaultMiddleware() ServerOption {
+	return func(cfg *serverOptionConfig) {
+		cfg.skipDefaultMW = true
+	}
+}
+
+// WithBasePath mounts every route under prefix (e.g. "/llm"), so "/v1/models" is served
+// at "/llm/v1/models". Requests outside the prefix receive 404. OAuth callback routes move
+// too, so provider redirect URLs must include the prefix.
+func WithBasePath(prefix string) ServerOption {
+	return func(cfg *serverOptionConfig) {
+		cfg.basePath = normalizeBasePath(prefix)
+	}
+}
+
 // Server represents the main API server.
 // It encapsulates the Gin engine, HTTP server, handlers, and configuration.
 type Server struct {
@@ -187,6 +220,11 @@ type Server struct {
 	keepAliveOnTimeout func()
 	keepAliveHeartbeat chan struct{}
 	keepAliveStop      chan struct{}
+
+	// listener, when set, replaces the listener Start would otherwise open.
+	listener net.Listener
+	// basePath is the prefix every route is mounted under; empty means root.
+	basePath string
 }
 
 // NewServer creates and initializes a new API server instance.
@@ -229,8 +267,10 @@ func NewServer(cfg *config.Config, authManager *auth.Manager, accessManager *sdk
 	}
 
 	// Add middleware
-	engine.Use(logging.GinLogrusLogger())
-	engine.Use(logging.GinLogrusRecovery())
+	if !optionState.skipDefaultMW {
+		engine.Use(logging.GinLogrusLogger())
+		engine.Use(logging.GinLogrusRecovery())
+	}
 	for _, mw := range optionState.extraMiddleware {
 		engine.Use(mw)
 	}
@@ -252,6 +292,7 @@ func NewServer(cfg *config.Config, authManager *auth.Manager, accessManager *sdk
 	}
 
 	engine.Use(corsMiddleware())
+	engine.Use(middleware.LoadSheddingMiddleware())
 	wd, err := os.Getwd()
 	if err != nil {
 		wd = configFilePath
@@ -273,6 +314,8 @@ func NewServer(cfg *config.Config, authManager *auth.Manager, accessManager *sdk
 		currentPath:         wd,
 		envManagementSecret: envManagementSecret,
 		wsRoutes:            make(map[string]struct{}),
+		listener:            optionState.listener,
+		basePath:            optionState.basePath,
 	}
 	s.wsAuthEnabled.Store(cfg.WebsocketAuth)
 	// Save initial YAML snapshot
@@ -284,6 +327,7 @@ func NewServer(cfg *config.Config, authManager *auth.Manager, accessManager *sdk
 	managementasset.SetCurrentConfig(cfg)
 	auth.SetQuotaCooldownDisabled(cfg.DisableCooling)
 	applySignatureCacheConfig(nil, cfg)
+	middleware.SetPinnedHistoryFallbackModels(cfg.PinnedHistoryFallbackModels)
 	// Initialize management handler
 	s.mgmt = managementHandlers.NewHandler(cfg, configFilePath, authManager)
 	if optionState.localPassword != "" {
@@ -330,9 +374,17 @@ func NewServer(cfg *config.Config, authManager *auth.Manager, accessManager *sdk
 	}
 
 	// Create HTTP server
+	var handler http.Handler = engine
+	if s.basePath != "" {
+		handler = basePathHandler(s.basePath, engine)
+	}
+	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	if s.listener != nil {
+		addr = s.listener.Addr().String()
+	}
 	s.server = &http.Server{
-		Addr:    fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
-		Handler: engine,
+		Addr:    addr,
+		Handler: handler,
 	}
 
 	return s
@@ -361,7 +413,7 @@ func (s *Server) setupRoutes() {
 
 	// OpenAI compatible API routes
 	v1 := s.engine.Group("/v1")
-	v1.Use(AuthMiddleware(s.accessManager))
+	v1.Use(AuthMiddleware(s.accessManager), middleware.ClientPacingMiddleware())
 	{
 		v1.GET("/models", s.unifiedModelsHandler(openaiHandlers, claudeCodeHandlers))
 		v1.POST("/chat/completions", openaiHandlers.ChatCompletions)
@@ -377,7 +429,7 @@ func (s *Server) setupRoutes() {
 
 	// Codex CLI direct route aliases (chatgpt_base_url compatible)
 	codexDirect := s.engine.Group("/backend-api/codex")
-	codexDirect.Use(AuthMiddleware(s.accessManager))
+	codexDirect.Use(AuthMiddleware(s.accessManager), middleware.ClientPacingMiddleware())
 	{
 		codexDirect.GET("/responses", openaiResponsesHandlers.ResponsesWebsocket)
 		codexDirect.POST("/responses", openaiResponsesHandlers.Responses)
@@ -386,7 +438,7 @@ func (s *Server) setupRoutes() {
 
 	// Gemini compatible API routes
 	v1beta := s.engine.Group("/v1beta")
-	v1beta.Use(AuthMiddleware(s.accessManager))
+	v1beta.Use(AuthMiddleware(s.accessManager), middleware.ClientPacingMiddleware())
 	{
 		v1beta.GET("/models", geminiHandlers.GeminiModels)
 		v1beta.POST("/models/*action", geminiHandlers.GeminiHandler)
@@ -394,8 +446,14 @@ func (s *Server) setupRoutes() {
 	}
 
 	// Health check endpoint
+	// Warm-up progress is summarised without per-account detail since /health is unauthenticated.
 	s.engine.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		resp := gin.H{"status": "ok"}
+		if st := warmup.CurrentStatus(); !st.StartedAt.IsZero() {
+			st.Results = nil
+			resp["warmup"] = st
+		}
+		c.JSON(http.StatusOK, resp)
 	})
 
 	// Root endpoint
@@ -572,6 +630,31 @@ func (s *Server) registerManagementRoutes() {
 
 		mgmt.POST("/api-call", s.mgmt.APICall)
 
+		mgmt.GET("/offline", s.mgmt.GetOffline)
+		mgmt.PUT("/offline", s.mgmt.PutOffline)
+		mgmt.PATCH("/offline", s.mgmt.PutOffline)
+		mgmt.GET("/agent-debug", s.mgmt.GetAgentDebug)
+		mgmt.PUT("/agent-debug", s.mgmt.PutAgentDebug)
+		mgmt.PATCH("/agent-debug", s.mgmt.PutAgentDebug)
+		mgmt.DELETE("/agent-debug", s.mgmt.DeleteAgentDebug)
+		mgmt.GET("/translator-failures", s.mgmt.GetTranslatorFailures)
+		mgmt.GET("/translator-failures/samples/:name", s.mgmt.GetTranslatorFailureSample)
+		mgmt.DELETE("/translator-failures", s.mgmt.DeleteTranslatorFailures)
+		mgmt.GET("/warmup", s.mgmt.GetWarmup)
+		mgmt.GET("/load-shedding", s.mgmt.GetLoadShedding)
+		mgmt.GET("/maintenance-windows", s.mgmt.GetMaintenanceWindows)
+		mgmt.GET("/pinned-sessions", s.mgmt.GetPinnedSessions)
+		mgmt.PUT("/pinned-sessions", s.mgmt.PutPinnedSession)
+		mgmt.DELETE("/pinned-sessions", s.mgmt.DeletePinnedSession)
+		mgmt.GET("/inflight", s.mgmt.GetInflight)
+		mgmt.DELETE("/inflight", s.mgmt.DeleteInflight)
+		mgmt.DELETE("/inflight/:id", s.mgmt.DeleteInflight)
+
+		mgmt.GET("/memory/workspaces", s.mgmt.ListMemoryWorkspaces)
+		mgmt.PUT("/memory/workspaces", s.mgmt.PutMemoryWorkspace)
+		mgmt.POST("/memory/workspaces", s.mgmt.PutMemoryWorkspace)
+		mgmt.DELETE("/memory/workspaces", s.mgmt.DeleteMemoryWorkspace)
+
 		mgmt.GET("/quota-exceeded/switch-project", s.mgmt.GetSwitchProject)
 		mgmt.PUT("/quota-exceeded/switch-project", s.mgmt.PutSwitchProject)
 		mgmt.PATCH("/quota-exceeded/switch-project", s.mgmt.PutSwitchProject)
@@ -676,6 +759,7 @@ func (s *Server) registerManagementRoutes() {
 		mgmt.GET("/model-definitions/:channel", s.mgmt.GetStaticModelDefinitions)
 		mgmt.GET("/auth-files/download", s.mgmt.DownloadAuthFile)
 		mgmt.POST("/auth-files", s.mgmt.UploadAuthFile)
+		mgmt.POST("/accounts/test", s.mgmt.PostAccountsTest)
 		mgmt.DELETE("/auth-files", s.mgmt.DeleteAuthFile)
 		mgmt.PATCH("/auth-files/status", s.mgmt.PatchAuthFileStatus)
 		mgmt.PATCH("/auth-files/fields", s.mgmt.PatchAuthFileFields)
@@ -841,11 +925,19 @@ func (s *Server) Start() error {
 		return fmt.Errorf("failed to start HTTP server: server not initialized")
 	}
 
-	addr := s.server.Addr
-	listener, errListen := net.Listen("tcp", addr)
-	if errListen != nil {
-		return fmt.Errorf("failed to start HTTP server: %v", errListen)
+	listener := s.listener
+	if listener == nil {
+		var errListen error
+		if s.cfg != nil && len(s.cfg.Listen) > 0 {
+			listener, errListen = listenAll(s.cfg.Listen)
+		} else {
+			listener, errListen = net.Listen("tcp", s.server.Addr)
+		}
+		if errListen != nil {
+			return fmt.Errorf("failed to start HTTP server: %v", errListen)
+		}
 	}
+	addr := describeListener(listener)
 
 	useTLS := s.cfg != nil && s.cfg.TLS.Enable
 	if useTLS {
@@ -967,6 +1059,13 @@ func (s *Server) Stop(ctx context.Context) error {
 		return fmt.Errorf("failed to shutdown HTTP server: %v", err)
 	}
 
+	// Flush request logs queued by an asynchronous logger.
+	if closer, ok := s.requestLogger.(interface{ Close() error }); ok {
+		if errClose := closer.Close(); errClose != nil {
+			log.Debugf("failed to flush request logs: %v", errClose)
+		}
+	}
+
 	log.Debug("API server stopped")
 	return nil
 }
@@ -1060,6 +1159,7 @@ func (s *Server) UpdateClients(cfg *config.Config) {
 	}
 
 	applySignatureCacheConfig(oldCfg, cfg)
+	middleware.SetPinnedHistoryFallbackModels(cfg.PinnedHistoryFallbackModels)
 
 	if s.handlers != nil && s.handlers.AuthManager != nil {
 		s.handlers.AuthManager.SetRetryConfig(cfg.RequestRetry, time.Duration(cfg.MaxRetryInterval)*time.Second, cfg.MaxRetryCredentials)
@@ -1142,6 +1242,7 @@ func (s *Server) UpdateClients(cfg *config.Config) {
 	geminiAPIKeyCount := len(cfg.GeminiKey)
 	claudeAPIKeyCount := len(cfg.ClaudeKey)
 	codexAPIKeyCount := len(cfg.CodexKey)
+	groqAPIKeyCount := len(cfg.GroqKey)
 	vertexAICompatCount := len(cfg.VertexCompatAPIKey)
 	openAICompatCount := 0
 	for i := range cfg.OpenAICompatibility {
@@ -1152,13 +1253,14 @@ func (s *Server) UpdateClients(cfg *config.Config) {
 		openAICompatCount += len(entry.APIKeyEntries)
 	}
 
-	total := authEntries + geminiAPIKeyCount + claudeAPIKeyCount + codexAPIKeyCount + vertexAICompatCount + openAICompatCount
-	fmt.Printf("server clients and configuration updated: %d clients (%d auth entries + %d Gemini API keys + %d Claude API keys + %d Codex keys + %d Vertex-compat + %d OpenAI-compat)\n",
+	total := authEntries + geminiAPIKeyCount + claudeAPIKeyCount + codexAPIKeyCount + groqAPIKeyCount + vertexAICompatCount + openAICompatCount
+	fmt.Printf("server clients and configuration updated: %d clients (%d auth entries + %d Gemini API keys + %d Claude API keys + %d Codex keys + %d Groq API keys + %d Vertex-compat + %d OpenAI-compat)\n",
 		total,
 		authEntries,
 		geminiAPIKeyCount,
 		claudeAPIKeyCount,
 		codexAPIKeyCount,
+		groqAPIKeyCount,
 		vertexAICompatCount,
 		openAICompatCount,
 	)
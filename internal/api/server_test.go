@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -18,7 +20,7 @@ import (
 	sdkconfig "github.com/router-for-me/CLIProxyAPI/v6/sdk/config"
 )
 
-func newTestServer(t *testing.T) *Server {
+func newTestServer(t *testing.T, opts ...ServerOption) *Server {
 	t.Helper()
 
 	gin.SetMode(gin.TestMode)
@@ -44,7 +46,7 @@ func newTestServer(t *testing.T) *Server {
 	accessManager := sdkaccess.NewManager()
 
 	configPath := filepath.Join(tmpDir, "config.yaml")
-	return NewServer(cfg, authManager, accessManager, configPath)
+	return NewServer(cfg, authManager, accessManager, configPath, opts...)
 }
 
 func TestHealthz(t *testing.T) {
@@ -84,6 +86,42 @@ func TestHealthz(t *testing.T) {
 	})
 }
 
+func TestServerWithListenerAndBasePath(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := newTestServer(t, WithListener(ln), WithBasePath("/llm/"), WithoutDefaultMiddleware())
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Start() }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Stop(ctx)
+		<-errCh
+	})
+
+	base := "http://" + ln.Addr().String()
+	cases := []struct {
+		path string
+		want int
+	}{
+		{path: "/llm/healthz", want: http.StatusOK},
+		{path: "/healthz", want: http.StatusNotFound},
+		{path: "/llmx/healthz", want: http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		resp, errGet := http.Get(base + tc.path)
+		if errGet != nil {
+			t.Fatalf("GET %s: %v", tc.path, errGet)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("GET %s = %d, want %d", tc.path, resp.StatusCode, tc.want)
+		}
+	}
+}
+
 func TestAmpProviderModelRoutes(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/filelock"
 )
 
 // KiroTokenStorage holds the persistent token data for Kiro authentication.
@@ -49,7 +51,7 @@ func (s *KiroTokenStorage) SaveTokenToFile(authFilePath string) error {
 		return fmt.Errorf("failed to marshal token storage: %w", err)
 	}
 
-	if err := os.WriteFile(authFilePath, data, 0600); err != nil {
+	if err := filelock.WithLock(authFilePath, func() error { return os.WriteFile(authFilePath, data, 0600) }); err != nil {
 		return fmt.Errorf("failed to write token file: %w", err)
 	}
 
@@ -182,21 +182,8 @@ func SetupCodexSafe(cfg *config.Config) SetupResult {
 	existingConfig["model_provider"] = "cliproxyapi"
 	existingConfig["base_url"] = fmt.Sprintf("http://127.0.0.1:%d/v1", port)
 
-	// Write config.toml preserving other settings
-	var configBuilder strings.Builder
-	configBuilder.WriteString("# Modified by ProxyPilot (original settings preserved)\n")
-	for k, v := range existingConfig {
-		switch val := v.(type) {
-		case string:
-			configBuilder.WriteString(fmt.Sprintf("%s = %q\n", k, val))
-		case bool:
-			configBuilder.WriteString(fmt.Sprintf("%s = %v\n", k, val))
-		case int, int64, float64:
-			configBuilder.WriteString(fmt.Sprintf("%s = %v\n", k, val))
-		}
-	}
-
-	if err := os.WriteFile(configPath, []byte(configBuilder.String()), 0644); err != nil {
+	// Write config.toml preserving other settings (including tables such as workspace profiles)
+	if err := writeCodexConfig(configPath, existingConfig); err != nil {
 		return SetupResult{
 			CLI:     "Codex CLI",
 			Success: false,
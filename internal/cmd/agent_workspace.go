@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/memory"
+)
+
+// WorkspaceHeader is the request header that pins requests to a registered memory workspace.
+const WorkspaceHeader = "X-ProxyPilot-Workspace"
+
+// WorkspaceOptions describes a project directory to register for memory retrieval.
+type WorkspaceOptions struct {
+	// Dir is the project root. Relative paths are resolved against the working directory.
+	Dir string
+	// Namespace overrides the semantic memory namespace. Defaults to the directory name.
+	Namespace string
+	// Description is an optional note shown in the management API.
+	Description string
+}
+
+// RegisterWorkspace records opts in the memory workspace registry and returns the stored entry.
+func RegisterWorkspace(opts WorkspaceOptions) (memory.Workspace, error) {
+	dir := strings.TrimSpace(opts.Dir)
+	if dir == "" {
+		return memory.Workspace{}, fmt.Errorf("workspace directory is required")
+	}
+	abs, err := filepath.Abs(expandPath(dir))
+	if err != nil {
+		return memory.Workspace{}, fmt.Errorf("failed to resolve workspace path: %w", err)
+	}
+	store := memory.NewFileStore(memory.DefaultBaseDir())
+	return store.RegisterWorkspace(memory.Workspace{
+		Path:        abs,
+		Namespace:   opts.Namespace,
+		Description: opts.Description,
+	})
+}
+
+// DoSetupClaudeWorkspace registers the workspace and writes the workspace header into the
+// project's .claude/settings.local.json so Claude Code sends it on every request.
+func DoSetupClaudeWorkspace(cfg *config.Config, opts WorkspaceOptions) {
+	printSetupResult(SetupClaudeWorkspaceSafe(cfg, opts))
+}
+
+// SetupClaudeWorkspaceSafe is DoSetupClaudeWorkspace without printing.
+func SetupClaudeWorkspaceSafe(_ *config.Config, opts WorkspaceOptions) SetupResult {
+	ws, err := RegisterWorkspace(opts)
+	if err != nil {
+		return SetupResult{CLI: "Claude Code workspace", Message: err.Error()}
+	}
+
+	settingsPath := filepath.Join(filepath.FromSlash(ws.Path), ".claude", "settings.local.json")
+	backupPath, err := backupFile(settingsPath)
+	if err != nil {
+		return SetupResult{CLI: "Claude Code workspace", Message: fmt.Sprintf("Failed to backup config: %v", err)}
+	}
+
+	settings := readJSONFile(settingsPath)
+	if settings == nil {
+		settings = make(map[string]any)
+	}
+	envMap, ok := settings["env"].(map[string]any)
+	if !ok {
+		envMap = make(map[string]any)
+	}
+	existing, _ := envMap["ANTHROPIC_CUSTOM_HEADERS"].(string)
+	envMap["ANTHROPIC_CUSTOM_HEADERS"] = mergeHeaderLines(existing, WorkspaceHeader, ws.Namespace)
+	settings["env"] = envMap
+
+	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+		return SetupResult{CLI: "Claude Code workspace", Message: fmt.Sprintf("Failed to create config directory: %v", err)}
+	}
+	if err := writeJSONFile(settingsPath, settings); err != nil {
+		return SetupResult{CLI: "Claude Code workspace", Message: fmt.Sprintf("Failed to write config: %v", err)}
+	}
+	return SetupResult{
+		CLI:        "Claude Code workspace",
+		Success:    true,
+		Message:    fmt.Sprintf("Workspace %s registered as namespace %q.", ws.Path, ws.Namespace),
+		BackupPath: backupPath,
+		ConfigPath: settingsPath,
+	}
+}
+
+// DoSetupCodexWorkspace registers the workspace and adds a Codex profile named after the
+// namespace whose provider sends the workspace header. Run codex with --profile <namespace>.
+func DoSetupCodexWorkspace(cfg *config.Config, opts WorkspaceOptions) {
+	printSetupResult(SetupCodexWorkspaceSafe(cfg, opts))
+}
+
+// SetupCodexWorkspaceSafe is DoSetupCodexWorkspace without printing.
+func SetupCodexWorkspaceSafe(cfg *config.Config, opts WorkspaceOptions) SetupResult {
+	ws, err := RegisterWorkspace(opts)
+	if err != nil {
+		return SetupResult{CLI: "Codex CLI workspace", Message: err.Error()}
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 8317
+	}
+
+	configPath := filepath.Join(expandPath("~/.codex"), "config.toml")
+	backupPath, err := backupFile(configPath)
+	if err != nil {
+		return SetupResult{CLI: "Codex CLI workspace", Message: fmt.Sprintf("Failed to backup config.toml: %v", err)}
+	}
+	existing := make(map[string]any)
+	if data, errRead := os.ReadFile(configPath); errRead == nil {
+		_ = toml.Unmarshal(data, &existing)
+	}
+
+	providerID := "proxypilot-" + codexProfileKey(ws.Namespace)
+	providers := tomlTable(existing, "model_providers")
+	providers[providerID] = map[string]any{
+		"name":         "ProxyPilot (" + ws.Namespace + ")",
+		"base_url":     fmt.Sprintf("http://127.0.0.1:%d/v1", port),
+		"wire_api":     "responses",
+		"env_key":      "OPENAI_API_KEY",
+		"http_headers": map[string]any{WorkspaceHeader: ws.Namespace},
+	}
+	profiles := tomlTable(existing, "profiles")
+	profile, _ := profiles[codexProfileKey(ws.Namespace)].(map[string]any)
+	if profile == nil {
+		profile = make(map[string]any)
+	}
+	profile["model_provider"] = providerID
+	profiles[codexProfileKey(ws.Namespace)] = profile
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return SetupResult{CLI: "Codex CLI workspace", Message: fmt.Sprintf("Failed to create config directory: %v", err)}
+	}
+	if err := writeCodexConfig(configPath, existing); err != nil {
+		return SetupResult{CLI: "Codex CLI workspace", Message: fmt.Sprintf("Failed to write config.toml: %v", err)}
+	}
+	return SetupResult{
+		CLI:     "Codex CLI workspace",
+		Success: true,
+		Message: fmt.Sprintf("Workspace %s registered as namespace %q. Run codex with --profile %s inside it.",
+			ws.Path, ws.Namespace, codexProfileKey(ws.Namespace)),
+		BackupPath: backupPath,
+		ConfigPath: configPath,
+	}
+}
+
+// mergeHeaderLines sets name: value in a newline-separated "Name: Value" header list,
+// replacing any existing entry for name.
+func mergeHeaderLines(existing, name, value string) string {
+	lines := make([]string, 0, 4)
+	for _, line := range strings.Split(existing, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if key, _, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(key), name) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, name+": "+value)
+	return strings.Join(lines, "\n")
+}
+
+// codexProfileKey turns a namespace into a bare TOML key usable as a profile name.
+func codexProfileKey(namespace string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(namespace) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('-')
+		}
+	}
+	if out := strings.Trim(b.String(), "-"); out != "" {
+		return out
+	}
+	return "workspace"
+}
+
+// tomlTable returns the nested table stored under key, creating it when missing.
+func tomlTable(root map[string]any, key string) map[string]any {
+	if t, ok := root[key].(map[string]any); ok {
+		return t
+	}
+	t := make(map[string]any)
+	root[key] = t
+	return t
+}
+
+// writeCodexConfig writes a Codex config.toml, keeping nested tables intact.
+func writeCodexConfig(path string, cfg map[string]any) error {
+	data, err := toml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	out := append([]byte("# Modified by ProxyPilot (original settings preserved)\n"), data...)
+	return os.WriteFile(path, out, 0644)
+}
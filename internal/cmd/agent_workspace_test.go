@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/memory"
+)
+
+func TestMergeHeaderLines(t *testing.T) {
+	got := mergeHeaderLines("X-Team: core\nx-proxypilot-workspace: old\n", WorkspaceHeader, "app")
+	if got != "X-Team: core\nX-ProxyPilot-Workspace: app" {
+		t.Fatalf("mergeHeaderLines() = %q", got)
+	}
+}
+
+func TestSetupWorkspaceWritesAgentConfigs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("CLIPROXY_MEMORY_DIR", filepath.Join(home, "memory"))
+	project := filepath.Join(home, "src", "My App")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	codexDir := filepath.Join(home, ".codex")
+	if err := os.MkdirAll(codexDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	existing := "model = \"gpt-5\"\n\n[projects.\"/tmp\"]\ntrust_level = \"trusted\"\n"
+	if err := os.WriteFile(filepath.Join(codexDir, "config.toml"), []byte(existing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Port: 9000}
+	if res := SetupClaudeWorkspaceSafe(cfg, WorkspaceOptions{Dir: project}); !res.Success {
+		t.Fatalf("SetupClaudeWorkspaceSafe() failed: %s", res.Message)
+	}
+	settings := readJSONFile(filepath.Join(project, ".claude", "settings.local.json"))
+	env, _ := settings["env"].(map[string]any)
+	if got, _ := env["ANTHROPIC_CUSTOM_HEADERS"].(string); got != WorkspaceHeader+": My App" {
+		t.Fatalf("ANTHROPIC_CUSTOM_HEADERS = %q", got)
+	}
+
+	if res := SetupCodexWorkspaceSafe(cfg, WorkspaceOptions{Dir: project}); !res.Success {
+		t.Fatalf("SetupCodexWorkspaceSafe() failed: %s", res.Message)
+	}
+	data, err := os.ReadFile(filepath.Join(codexDir, "config.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed map[string]any
+	if err := toml.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("config.toml is not valid TOML: %v\n%s", err, data)
+	}
+	if parsed["model"] != "gpt-5" || parsed["projects"] == nil {
+		t.Fatalf("existing settings were not preserved:\n%s", data)
+	}
+	if !strings.Contains(string(data), "proxypilot-my-app") || !strings.Contains(string(data), WorkspaceHeader) {
+		t.Fatalf("profile/provider missing:\n%s", data)
+	}
+
+	ws, ok := memory.NewFileStore(memory.DefaultBaseDir()).ResolveWorkspace(filepath.Join(project, "pkg"))
+	if !ok || ws.Namespace != "My App" {
+		t.Fatalf("workspace not registered: %+v, %v", ws, ok)
+	}
+}
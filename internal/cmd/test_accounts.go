@@ -0,0 +1,152 @@
+// Package cmd provides CLI command implementations for ProxyPilot.
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/config"
+)
+
+// AccountTestResult is the outcome of testing one account, as reported by the proxy.
+type AccountTestResult struct {
+	AuthID    string `json:"auth_id"`
+	Provider  string `json:"provider"`
+	Label     string `json:"label,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Model     string `json:"model,omitempty"`
+	Models    int    `json:"models"`
+	OK        bool   `json:"ok"`
+	Skipped   bool   `json:"skipped,omitempty"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// AccountTestReport summarises an account test run.
+type AccountTestReport struct {
+	Total     int                 `json:"total"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Results   []AccountTestResult `json:"results"`
+}
+
+// TestAccounts asks the running proxy to send a minimal generation through every enabled
+// account and prints success, latency and model availability per account. The proxy must
+// be running locally; managementKey authenticates against its management API.
+// It returns an error when any account fails so scripts can check the exit status.
+func TestAccounts(cfg *config.Config, managementKey string, concurrency int, jsonOutput bool) error {
+	if cfg == nil {
+		return errors.New("config is nil")
+	}
+	scheme := "http"
+	if cfg.TLS.Enable {
+		scheme = "https"
+	}
+	url := fmt.Sprintf("%s://127.0.0.1:%d/v0/management/accounts/test", scheme, cfg.Port)
+	body, _ := json.Marshal(map[string]any{"concurrency": concurrency})
+
+	if !jsonOutput {
+		fmt.Printf("\n%s%sTesting accounts...%s\n", colorBold, colorCyan, colorReset)
+		fmt.Printf("%s─────────────────────────────%s\n\n", colorDim, colorReset)
+	}
+
+	report, err := requestAccountTest(url, managementKey, body)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		if errOutput := outputJSON(report); errOutput != nil {
+			return errOutput
+		}
+	} else {
+		printAccountTestReport(report)
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d account(s) failed", report.Failed, report.Total)
+	}
+	return nil
+}
+
+func requestAccountTest(url, managementKey string, body []byte) (*AccountTestReport, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if managementKey != "" {
+		req.Header.Set("X-Management-Key", managementKey)
+	}
+	client := &http.Client{
+		// Accounts are tested server-side with their own per-request timeout.
+		Timeout: 30 * time.Minute,
+		Transport: &http.Transport{
+			// The local proxy commonly uses a self-signed certificate when TLS is enabled.
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		var netErr *net.OpError
+		if errors.As(err, &netErr) {
+			return nil, errors.New("ProxyPilot is not running - start it first")
+		}
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("management API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	var report AccountTestReport
+	if err = json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &report, nil
+}
+
+func printAccountTestReport(report *AccountTestReport) {
+	if len(report.Results) == 0 {
+		fmt.Printf("%sNo enabled accounts found%s\n\n", colorYellow, colorReset)
+		return
+	}
+	for _, r := range report.Results {
+		name := r.Email
+		if name == "" {
+			name = r.Label
+		}
+		if name == "" {
+			name = r.AuthID
+		}
+		switch {
+		case r.OK:
+			fmt.Printf("  %s✓%s %-12s %s %s(%s, %dms, %d models)%s\n", colorGreen, colorReset, r.Provider, name, colorDim, r.Model, r.LatencyMs, r.Models, colorReset)
+		case r.Skipped:
+			fmt.Printf("  %s-%s %-12s %s %s(%s)%s\n", colorYellow, colorReset, r.Provider, name, colorDim, r.Error, colorReset)
+		default:
+			fmt.Printf("  %s✗%s %-12s %s %s(%s, %dms)%s\n", colorRed, colorReset, r.Provider, name, colorDim, r.Model, r.LatencyMs, colorReset)
+			fmt.Printf("      %s%s%s\n", colorRed, r.Error, colorReset)
+		}
+	}
+
+	fmt.Printf("\n%s─────────────────────────────%s\n", colorDim, colorReset)
+	fmt.Printf("Tested: %s%d succeeded%s", colorGreen, report.Succeeded, colorReset)
+	if report.Failed > 0 {
+		fmt.Printf(", %s%d failed%s", colorRed, report.Failed, colorReset)
+	}
+	if skipped := report.Total - report.Succeeded - report.Failed; skipped > 0 {
+		fmt.Printf(", %s%d skipped%s", colorYellow, skipped, colorReset)
+	}
+	fmt.Printf("\n\n")
+}
@@ -14,6 +14,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/filelock"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/registry"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
@@ -33,6 +34,9 @@ type Config struct {
 	Host string `yaml:"host" json:"-"`
 	// Port is the network port on which the API server will listen.
 	Port int `yaml:"port" json:"-"`
+	// Listen lists addresses to bind instead of host/port, e.g. "127.0.0.1:8317",
+	// "[::1]:8317" or "unix:/run/proxypilot.sock". All entries are served simultaneously.
+	Listen []string `yaml:"listen,omitempty" json:"-"`
 
 	// TLS config controls HTTPS server settings.
 	TLS TLSConfig `yaml:"tls" json:"tls"`
@@ -63,6 +67,14 @@ type Config struct {
 	// When exceeded, the oldest error log files are deleted. Default is 10. Set to 0 to disable cleanup.
 	ErrorLogsMaxFiles int `yaml:"error-logs-max-files" json:"error-logs-max-files"`
 
+	// RequestLogAsync writes non-streaming request logs from a background worker instead of
+	// the request path. Takes effect on restart.
+	RequestLogAsync bool `yaml:"request-log-async" json:"request-log-async"`
+
+	// RequestLogBufferSize bounds the async request log queue. When full, the oldest queued
+	// entry is dropped. Default is 256.
+	RequestLogBufferSize int `yaml:"request-log-buffer-size,omitempty" json:"request-log-buffer-size,omitempty"`
+
 	// UsageStatisticsEnabled toggles in-memory usage aggregation; when false, usage data is discarded.
 	UsageStatisticsEnabled bool `yaml:"usage-statistics-enabled" json:"usage-statistics-enabled"`
 
@@ -97,6 +109,11 @@ type Config struct {
 
 	AntigravitySignatureBypassStrict *bool `yaml:"antigravity-signature-bypass-strict,omitempty" json:"antigravity-signature-bypass-strict,omitempty"`
 
+	// AntigravitySuppressThoughts strips thought parts from streamed Antigravity responses.
+	// Thinking tokens are still reported in usage. Clients can override it per request with
+	// the X-ProxyPilot-Suppress-Thoughts header.
+	AntigravitySuppressThoughts bool `yaml:"antigravity-suppress-thoughts,omitempty" json:"antigravity-suppress-thoughts,omitempty"`
+
 	// GeminiKey defines Gemini API key configurations with optional routing overrides.
 	GeminiKey []GeminiKey `yaml:"gemini-api-key" json:"gemini-api-key"`
 
@@ -110,6 +127,9 @@ type Config struct {
 	// ClaudeKey defines a list of Claude API key configurations as specified in the YAML configuration file.
 	ClaudeKey []ClaudeKey `yaml:"claude-api-key" json:"claude-api-key"`
 
+	// GroqKey defines a list of Groq API key configurations as specified in the YAML configuration file.
+	GroqKey []GroqKey `yaml:"groq-api-key" json:"groq-api-key"`
+
 	// ClaudeHeaderDefaults configures default header values for Claude API requests.
 	// These are used as fallbacks when the client does not send its own headers.
 	ClaudeHeaderDefaults ClaudeHeaderDefaults `yaml:"claude-header-defaults" json:"claude-header-defaults"`
@@ -144,6 +164,10 @@ type Config struct {
 	// ThinkingBudget defines default thinking token budget settings.
 	ThinkingBudget ThinkingBudgetConfig `yaml:"thinking-budget" json:"thinking-budget"`
 
+	// ThinkingLevels overrides the reasoning-effort level → thinking budget mapping used
+	// when a level (e.g. "low", "high") is converted for budget-based models.
+	ThinkingLevels ThinkingLevelsConfig `yaml:"thinking-levels" json:"thinking-levels"`
+
 	// Updates controls automatic update checking behavior.
 	Updates UpdatesConfig `yaml:"updates" json:"updates"`
 
@@ -152,6 +176,38 @@ type Config struct {
 	// from your current session. Default: false.
 	IncognitoBrowser bool `yaml:"incognito-browser" json:"incognito-browser"`
 
+	// Offline controls offline mode, where upstream provider calls are refused and only
+	// local providers, cached model listings, and management APIs remain available.
+	Offline OfflineConfig `yaml:"offline" json:"offline"`
+
+	// AgentDebug controls the in-memory diagnostics channel used when investigating
+	// provider behaviour. Disabled by default; entries are only kept in memory.
+	AgentDebug AgentDebugConfig `yaml:"agent-debug" json:"agent-debug"`
+
+	// TranslatorDebug controls quarantining of payloads that made a translator fail.
+	// Failure counters are always kept; samples are only written when enabled.
+	TranslatorDebug TranslatorDebugConfig `yaml:"translator-debug" json:"translator-debug"`
+
+	// Warmup sends a tiny generation per backing account for the listed models at startup
+	// and after config reloads, so the first real request does not pay connection setup costs.
+	Warmup WarmupConfig `yaml:"warmup" json:"warmup"`
+
+	// LoadShedding rejects low-priority requests with 503 while CPU or memory usage is
+	// above the configured watermarks.
+	LoadShedding LoadSheddingConfig `yaml:"load-shedding" json:"load-shedding"`
+
+	// ClientPacing advertises (and optionally enforces) per-client request and token budgets
+	// through OpenAI-compatible x-ratelimit-* response headers.
+	ClientPacing ClientPacingConfig `yaml:"client-pacing" json:"client-pacing"`
+
+	// MaintenanceWindows lists recurring per-account windows during which the matching
+	// credentials are excluded from routing, e.g. while their owner uses them interactively.
+	MaintenanceWindows []MaintenanceWindow `yaml:"maintenance-windows,omitempty" json:"maintenance-windows,omitempty"`
+
+	// PinnedHistoryFallbackModels lists larger-context models, in order of preference, that
+	// sessions pinned to full history switch to once they outgrow the requested model.
+	PinnedHistoryFallbackModels []string `yaml:"pinned-history-fallback-models,omitempty" json:"pinned-history-fallback-models,omitempty"`
+
 	legacyMigrationPending bool `yaml:"-" json:"-"`
 }
 
@@ -185,10 +241,15 @@ func ValidateConfig(cfg *Config) (warnings []string, err error) {
 	if cfg == nil {
 		return nil, errors.New("config is nil")
 	}
-	// Validate port range
-	if cfg.Port <= 0 || cfg.Port > 65535 {
+	// Validate port range. Port may be omitted when only Unix sockets are listed.
+	if (cfg.Port != 0 || len(cfg.Listen) == 0) && (cfg.Port <= 0 || cfg.Port > 65535) {
 		return nil, fmt.Errorf("port must be between 1 and 65535, got %d", cfg.Port)
 	}
+	for _, entry := range cfg.Listen {
+		if _, _, errParse := ParseListenAddress(entry); errParse != nil {
+			return nil, errParse
+		}
+	}
 	return nil, nil
 }
 
@@ -326,6 +387,23 @@ type ThinkingBudgetConfig struct {
 	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
 }
 
+// ThinkingLevelsConfig holds the configurable level → thinking budget tables.
+type ThinkingLevelsConfig struct {
+	// Budgets overrides the default budget per level for all models.
+	// Keys are levels (none, auto, minimal, low, medium, high, xhigh, max).
+	Budgets map[string]int `yaml:"budgets,omitempty" json:"budgets,omitempty"`
+	// Models lists per-model overrides; the first rule matching the model and level wins.
+	Models []ThinkingLevelModelRule `yaml:"models,omitempty" json:"models,omitempty"`
+}
+
+// ThinkingLevelModelRule maps levels to budgets for models matching Name.
+type ThinkingLevelModelRule struct {
+	// Name is the model name or wildcard pattern (e.g., "gemini-2.5-*", "claude-*-sonnet-*").
+	Name string `yaml:"name" json:"name"`
+	// Budgets maps levels to thinking budgets for matching models.
+	Budgets map[string]int `yaml:"budgets" json:"budgets"`
+}
+
 // IsEnabled returns true if thinking budget is enabled.
 func (tb *ThinkingBudgetConfig) IsEnabled() bool {
 	return tb.Enabled == nil || *tb.Enabled
@@ -391,6 +469,187 @@ func (u UpdatesConfig) IsNotifyOnUpdateEnabled() bool {
 	return u.NotifyOnUpdate
 }
 
+// OfflineConfig controls offline mode behavior.
+type OfflineConfig struct {
+	// Enabled forces offline mode regardless of connectivity.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// AutoDetect probes connectivity in the background and enters/leaves offline mode automatically.
+	AutoDetect bool `yaml:"auto-detect" json:"auto-detect"`
+	// ProbeAddr is the host:port or URL dialed by the connectivity probe.
+	// Defaults to "www.google.com:443".
+	ProbeAddr string `yaml:"probe-addr,omitempty" json:"probe-addr,omitempty"`
+	// ProbeIntervalSeconds controls how often connectivity is probed. Defaults to 30.
+	ProbeIntervalSeconds int `yaml:"probe-interval-seconds,omitempty" json:"probe-interval-seconds,omitempty"`
+	// LocalProviders lists provider keys (e.g. an openai-compatibility name such as "ollama")
+	// that remain usable while offline. Providers with loopback base URLs are always local.
+	LocalProviders []string `yaml:"local-providers,omitempty" json:"local-providers,omitempty"`
+}
+
+// GetProbeInterval returns the connectivity probe interval. Defaults to 30 seconds.
+func (o OfflineConfig) GetProbeInterval() time.Duration {
+	if o.ProbeIntervalSeconds <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(o.ProbeIntervalSeconds) * time.Second
+}
+
+// AgentDebugConfig controls the diagnostics ring buffer exposed via the management API.
+type AgentDebugConfig struct {
+	// Enabled turns on recording of diagnostics entries.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// MaxEntries is the ring buffer capacity. Defaults to 256.
+	MaxEntries int `yaml:"max-entries,omitempty" json:"max-entries,omitempty"`
+	// MaxEntryBytes caps the encoded size of an entry's data payload. Defaults to 4096.
+	MaxEntryBytes int `yaml:"max-entry-bytes,omitempty" json:"max-entry-bytes,omitempty"`
+}
+
+// TranslatorDebugConfig controls the quarantine of failed translator payloads.
+type TranslatorDebugConfig struct {
+	// Quarantine writes redacted samples of failed payloads to Dir.
+	Quarantine bool `yaml:"quarantine" json:"quarantine"`
+	// Dir is the quarantine directory. Defaults to "translator-quarantine" under the auth directory.
+	Dir string `yaml:"dir,omitempty" json:"dir,omitempty"`
+	// SampleRate is the fraction of failures quarantined, between 0 and 1. Defaults to 1.
+	SampleRate float64 `yaml:"sample-rate,omitempty" json:"sample-rate,omitempty"`
+	// MaxSamples caps the number of files kept; the oldest are pruned first. Defaults to 100.
+	MaxSamples int `yaml:"max-samples,omitempty" json:"max-samples,omitempty"`
+	// MaxSampleBytes caps each stored payload. Defaults to 65536.
+	MaxSampleBytes int `yaml:"max-sample-bytes,omitempty" json:"max-sample-bytes,omitempty"`
+}
+
+// WarmupConfig controls model warm-up requests sent at startup and after config reloads.
+type WarmupConfig struct {
+	// Enabled turns warm-up on. Nothing is sent when Models is empty.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// Models lists the model IDs to warm; every account able to serve a model is warmed.
+	Models []string `yaml:"models,omitempty" json:"models,omitempty"`
+	// Prompt is the user message sent for each warm-up. Defaults to "ping".
+	Prompt string `yaml:"prompt,omitempty" json:"prompt,omitempty"`
+	// DelaySeconds waits before warming so accounts finish loading. Defaults to 5.
+	DelaySeconds int `yaml:"delay-seconds,omitempty" json:"delay-seconds,omitempty"`
+	// TimeoutSeconds bounds each warm-up request. Defaults to 30.
+	TimeoutSeconds int `yaml:"timeout-seconds,omitempty" json:"timeout-seconds,omitempty"`
+	// Concurrency limits simultaneous warm-up requests. Defaults to 4.
+	Concurrency int `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
+}
+
+// GetPrompt returns the warm-up prompt. Defaults to "ping".
+func (w WarmupConfig) GetPrompt() string {
+	if p := strings.TrimSpace(w.Prompt); p != "" {
+		return p
+	}
+	return "ping"
+}
+
+// GetDelay returns the delay before warming starts. Defaults to 5 seconds.
+func (w WarmupConfig) GetDelay() time.Duration {
+	if w.DelaySeconds < 0 {
+		return 0
+	}
+	if w.DelaySeconds == 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(w.DelaySeconds) * time.Second
+}
+
+// GetTimeout returns the per-request warm-up timeout. Defaults to 30 seconds.
+func (w WarmupConfig) GetTimeout() time.Duration {
+	if w.TimeoutSeconds <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(w.TimeoutSeconds) * time.Second
+}
+
+// GetConcurrency returns the number of concurrent warm-up requests. Defaults to 4.
+func (w WarmupConfig) GetConcurrency() int {
+	if w.Concurrency <= 0 {
+		return 4
+	}
+	return w.Concurrency
+}
+
+// LoadSheddingConfig controls resource-based shedding of low-priority requests.
+type LoadSheddingConfig struct {
+	// Enabled turns shedding on. At least one watermark must also be set.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// MaxCPUPercent is the system CPU utilisation (0-100) above which shedding starts.
+	// Only sampled on Linux.
+	MaxCPUPercent float64 `yaml:"max-cpu-percent,omitempty" json:"max-cpu-percent,omitempty"`
+	// MaxMemoryPercent is the system memory utilisation (0-100) above which shedding starts.
+	// Only sampled on Linux.
+	MaxMemoryPercent float64 `yaml:"max-memory-percent,omitempty" json:"max-memory-percent,omitempty"`
+	// MaxProcessMemoryMB is the proxy's own memory footprint above which shedding starts.
+	MaxProcessMemoryMB int `yaml:"max-process-memory-mb,omitempty" json:"max-process-memory-mb,omitempty"`
+	// SampleIntervalSeconds controls how often usage is sampled. Defaults to 5.
+	SampleIntervalSeconds int `yaml:"sample-interval-seconds,omitempty" json:"sample-interval-seconds,omitempty"`
+	// RetryAfterSeconds is sent in the Retry-After header of shed responses. Defaults to 30.
+	RetryAfterSeconds int `yaml:"retry-after-seconds,omitempty" json:"retry-after-seconds,omitempty"`
+	// LowPriorityPaths lists request path substrings treated as batch traffic.
+	// Defaults to embedding endpoints. Requests sending "X-ProxyPilot-Priority: low"
+	// (or "batch") are always treated as low priority.
+	LowPriorityPaths []string `yaml:"low-priority-paths,omitempty" json:"low-priority-paths,omitempty"`
+}
+
+// DefaultLowPriorityPaths are shed when LowPriorityPaths is empty.
+var DefaultLowPriorityPaths = []string{"/embeddings", ":embedContent", ":batchEmbedContents"}
+
+// GetSampleInterval returns how often usage is sampled. Defaults to 5 seconds.
+func (l LoadSheddingConfig) GetSampleInterval() time.Duration {
+	if l.SampleIntervalSeconds <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(l.SampleIntervalSeconds) * time.Second
+}
+
+// GetRetryAfter returns the Retry-After delay for shed requests. Defaults to 30 seconds.
+func (l LoadSheddingConfig) GetRetryAfter() time.Duration {
+	if l.RetryAfterSeconds <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(l.RetryAfterSeconds) * time.Second
+}
+
+// GetLowPriorityPaths returns the configured low-priority path patterns or the defaults.
+func (l LoadSheddingConfig) GetLowPriorityPaths() []string {
+	out := make([]string, 0, len(l.LowPriorityPaths))
+	for _, p := range l.LowPriorityPaths {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	if len(out) == 0 {
+		return append([]string(nil), DefaultLowPriorityPaths...)
+	}
+	return out
+}
+
+// ClientPacingConfig configures per-client budgets counted in one-minute windows.
+type ClientPacingConfig struct {
+	// Enabled turns pacing on. At least one budget must also be set.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// RequestsPerMinute is the request budget per client key and minute.
+	RequestsPerMinute int `yaml:"requests-per-minute,omitempty" json:"requests-per-minute,omitempty"`
+	// TokensPerMinute is the token budget per client key and minute, counted from reported usage.
+	TokensPerMinute int `yaml:"tokens-per-minute,omitempty" json:"tokens-per-minute,omitempty"`
+	// Enforce rejects requests with 429 once a budget is exhausted. When false the budgets
+	// are only advertised in response headers.
+	Enforce bool `yaml:"enforce,omitempty" json:"enforce,omitempty"`
+}
+
+// MaintenanceWindow excludes matching credentials from routing on a recurring schedule.
+type MaintenanceWindow struct {
+	// Auth selects the credentials by auth ID, file name, label or account email.
+	// Shell-style wildcards such as "*@example.com" are supported.
+	Auth string `yaml:"auth" json:"auth"`
+	// Schedule is a five-field cron expression (minute hour day-of-month month day-of-week)
+	// describing when each window starts, e.g. "0 9 * * 1-5".
+	Schedule string `yaml:"schedule" json:"schedule"`
+	// DurationMinutes is how long each window lasts after it starts.
+	DurationMinutes int `yaml:"duration-minutes" json:"duration-minutes"`
+	// Timezone is the IANA zone used to evaluate Schedule. Defaults to the local zone.
+	Timezone string `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+}
+
 // AmpModelMapping defines a model name mapping for Amp CLI requests.
 // When Amp requests a model that isn't available locally, this mapping
 // allows routing to an alternative model that IS available.
@@ -563,6 +822,53 @@ type ClaudeModel struct {
 func (m ClaudeModel) GetName() string  { return m.Name }
 func (m ClaudeModel) GetAlias() string { return m.Alias }
 
+// GroqKey represents the configuration for a Groq API key. Groq exposes an
+// OpenAI-compatible endpoint; when Models is empty the available models are
+// discovered from the upstream /models listing.
+type GroqKey struct {
+	// APIKey is the authentication key for accessing Groq API services.
+	APIKey string `yaml:"api-key" json:"api-key"`
+
+	// Priority controls selection preference when multiple credentials match.
+	// Higher values are preferred; defaults to 0.
+	Priority int `yaml:"priority,omitempty" json:"priority,omitempty"`
+
+	// Prefix optionally namespaces models for this credential (e.g., "fast/llama-3.3-70b-versatile").
+	Prefix string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+
+	// BaseURL optionally overrides the Groq API endpoint.
+	// If empty, https://api.groq.com/openai/v1 is used.
+	BaseURL string `yaml:"base-url,omitempty" json:"base-url,omitempty"`
+
+	// ProxyURL overrides the global proxy setting for this API key if provided.
+	ProxyURL string `yaml:"proxy-url,omitempty" json:"proxy-url,omitempty"`
+
+	// Models defines upstream model names and aliases for request routing.
+	// When empty, models are discovered from the Groq API.
+	Models []GroqModel `yaml:"models,omitempty" json:"models,omitempty"`
+
+	// Headers optionally adds extra HTTP headers for requests sent with this key.
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+
+	// ExcludedModels lists model IDs that should be excluded for this provider.
+	ExcludedModels []string `yaml:"excluded-models,omitempty" json:"excluded-models,omitempty"`
+}
+
+func (k GroqKey) GetAPIKey() string  { return k.APIKey }
+func (k GroqKey) GetBaseURL() string { return k.BaseURL }
+
+// GroqModel describes a mapping between an alias and the actual upstream model name.
+type GroqModel struct {
+	// Name is the upstream model identifier used when issuing requests.
+	Name string `yaml:"name" json:"name"`
+
+	// Alias is the client-facing model name that maps to Name.
+	Alias string `yaml:"alias" json:"alias"`
+}
+
+func (m GroqModel) GetName() string  { return m.Name }
+func (m GroqModel) GetAlias() string { return m.Alias }
+
 // CodexKey represents the configuration for a Codex API key,
 // including the API key itself and an optional base URL for the API endpoint.
 type CodexKey struct {
@@ -819,6 +1125,9 @@ func LoadConfigOptional(configFile string, optional bool) (*Config, error) {
 		cfg.MaxRetryCredentials = 0
 	}
 
+	// Normalize listen addresses and derive port from them when unset.
+	cfg.SanitizeListen()
+
 	// Sanitize Gemini API key configuration and migrate legacy entries.
 	cfg.SanitizeGeminiKeys()
 
@@ -837,6 +1146,9 @@ func LoadConfigOptional(configFile string, optional bool) (*Config, error) {
 	// Sanitize Claude key headers
 	cfg.SanitizeClaudeKeys()
 
+	// Sanitize Groq keys: drop entries without api-key
+	cfg.SanitizeGroqKeys()
+
 	// Sanitize OpenAI compatibility providers: drop entries without base-url
 	cfg.SanitizeOpenAICompatibility()
 
@@ -1042,6 +1354,36 @@ func (cfg *Config) SanitizeClaudeKeys() {
 	}
 }
 
+// SanitizeGroqKeys deduplicates and normalizes Groq credentials.
+// It uses API key + base URL as the uniqueness key.
+func (cfg *Config) SanitizeGroqKeys() {
+	if cfg == nil {
+		return
+	}
+
+	seen := make(map[string]struct{}, len(cfg.GroqKey))
+	out := cfg.GroqKey[:0]
+	for i := range cfg.GroqKey {
+		entry := cfg.GroqKey[i]
+		entry.APIKey = strings.TrimSpace(entry.APIKey)
+		if entry.APIKey == "" {
+			continue
+		}
+		entry.Prefix = normalizeModelPrefix(entry.Prefix)
+		entry.BaseURL = strings.TrimSpace(entry.BaseURL)
+		entry.ProxyURL = strings.TrimSpace(entry.ProxyURL)
+		entry.Headers = NormalizeHeaders(entry.Headers)
+		entry.ExcludedModels = NormalizeExcludedModels(entry.ExcludedModels)
+		uniqueKey := entry.APIKey + "|" + entry.BaseURL
+		if _, exists := seen[uniqueKey]; exists {
+			continue
+		}
+		seen[uniqueKey] = struct{}{}
+		out = append(out, entry)
+	}
+	cfg.GroqKey = out
+}
+
 // SanitizeGeminiKeys deduplicates and normalizes Gemini credentials.
 // It uses API key + base URL as the uniqueness key.
 func (cfg *Config) SanitizeGeminiKeys() {
@@ -1171,6 +1513,12 @@ func hashSecret(secret string) (string, error) {
 // SaveConfigPreserveComments writes the config back to YAML while preserving existing comments
 // and key ordering by loading the original file into a yaml.Node tree and updating values in-place.
 func SaveConfigPreserveComments(configFile string, cfg *Config) error {
+	unlock, err := filelock.Lock(configFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	persistCfg := cfg
 	// Load original YAML as a node tree to preserve comments and ordering.
 	data, err := os.ReadFile(configFile)
@@ -1242,6 +1590,12 @@ func SaveConfigPreserveComments(configFile string, cfg *Config) error {
 // SaveConfigPreserveCommentsUpdateNestedScalar updates a nested scalar key path like ["a","b"]
 // while preserving comments and positions.
 func SaveConfigPreserveCommentsUpdateNestedScalar(configFile string, path []string, value string) error {
+	unlock, err := filelock.Lock(configFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	data, err := os.ReadFile(configFile)
 	if err != nil {
 		return err
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ParseListenAddress splits a listen entry into the network and address passed to net.Listen.
+//
+// Supported forms:
+//   - "unix:/path/to/proxypilot.sock", "unix:///path/to/proxypilot.sock" or a bare absolute path
+//   - "127.0.0.1:8317" (tcp4), "[::1]:8317" (tcp6), "localhost:8317" or ":8317" (tcp)
+//
+// IP literals are bound on their own address family so "0.0.0.0:8317" and "[::]:8317"
+// can be listed together for dual-stack hosts.
+func ParseListenAddress(entry string) (network, address string, err error) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return "", "", fmt.Errorf("listen address is empty")
+	}
+	if rest, ok := strings.CutPrefix(entry, "unix:"); ok {
+		rest = strings.TrimPrefix(rest, "//")
+		if strings.TrimSpace(rest) == "" {
+			return "", "", fmt.Errorf("listen address %q: unix socket path is empty", entry)
+		}
+		return "unix", rest, nil
+	}
+	if strings.HasPrefix(entry, "/") {
+		return "unix", entry, nil
+	}
+	host, port, errSplit := net.SplitHostPort(entry)
+	if errSplit != nil {
+		return "", "", fmt.Errorf("listen address %q: %w", entry, errSplit)
+	}
+	if p, errPort := strconv.Atoi(port); errPort != nil || p < 0 || p > 65535 {
+		return "", "", fmt.Errorf("listen address %q: invalid port %q", entry, port)
+	}
+	network = "tcp"
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.To4() != nil {
+			network = "tcp4"
+		} else {
+			network = "tcp6"
+		}
+	}
+	return network, entry, nil
+}
+
+// ListenAddresses returns the addresses the API server binds. When listen is not
+// configured the single host/port pair is used.
+func (cfg *Config) ListenAddresses() []string {
+	if cfg == nil {
+		return nil
+	}
+	if len(cfg.Listen) > 0 {
+		return append([]string(nil), cfg.Listen...)
+	}
+	return []string{net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))}
+}
+
+// SanitizeListen trims and deduplicates listen entries. When port is unset it is taken
+// from the first TCP entry, so local tooling that builds http://127.0.0.1:<port> URLs
+// keeps working.
+func (cfg *Config) SanitizeListen() {
+	if cfg == nil || len(cfg.Listen) == 0 {
+		return
+	}
+	seen := make(map[string]struct{}, len(cfg.Listen))
+	out := make([]string, 0, len(cfg.Listen))
+	for _, entry := range cfg.Listen {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, ok := seen[entry]; ok {
+			continue
+		}
+		seen[entry] = struct{}{}
+		out = append(out, entry)
+	}
+	cfg.Listen = out
+	if cfg.Port != 0 {
+		return
+	}
+	for _, entry := range cfg.Listen {
+		network, address, err := ParseListenAddress(entry)
+		if err != nil || network == "unix" {
+			continue
+		}
+		if _, port, errSplit := net.SplitHostPort(address); errSplit == nil {
+			if p, errPort := strconv.Atoi(port); errPort == nil && p > 0 {
+				cfg.Port = p
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import "testing"
+
+func TestParseListenAddress(t *testing.T) {
+	tests := []struct {
+		entry       string
+		wantNetwork string
+		wantAddress string
+		wantErr     bool
+	}{
+		{entry: "127.0.0.1:8317", wantNetwork: "tcp4", wantAddress: "127.0.0.1:8317"},
+		{entry: "[::]:8317", wantNetwork: "tcp6", wantAddress: "[::]:8317"},
+		{entry: "localhost:8317", wantNetwork: "tcp", wantAddress: "localhost:8317"},
+		{entry: ":8317", wantNetwork: "tcp", wantAddress: ":8317"},
+		{entry: "unix:/run/pp.sock", wantNetwork: "unix", wantAddress: "/run/pp.sock"},
+		{entry: "unix:///run/pp.sock", wantNetwork: "unix", wantAddress: "/run/pp.sock"},
+		{entry: "/tmp/pp.sock", wantNetwork: "unix", wantAddress: "/tmp/pp.sock"},
+		{entry: "8317", wantErr: true},
+		{entry: "127.0.0.1:99999", wantErr: true},
+		{entry: "unix:", wantErr: true},
+	}
+	for _, tt := range tests {
+		network, address, err := ParseListenAddress(tt.entry)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseListenAddress(%q) error = %v, wantErr %v", tt.entry, err, tt.wantErr)
+		}
+		if tt.wantErr {
+			continue
+		}
+		if network != tt.wantNetwork || address != tt.wantAddress {
+			t.Errorf("ParseListenAddress(%q) = %s %s, want %s %s", tt.entry, network, address, tt.wantNetwork, tt.wantAddress)
+		}
+	}
+}
+
+func TestSanitizeListenDerivesPort(t *testing.T) {
+	cfg := &Config{Listen: []string{" unix:/run/pp.sock ", "[::1]:9000", "127.0.0.1:9000", "[::1]:9000", ""}}
+	cfg.SanitizeListen()
+	if len(cfg.Listen) != 3 {
+		t.Fatalf("Listen = %v, want 3 deduplicated entries", cfg.Listen)
+	}
+	if cfg.Port != 9000 {
+		t.Fatalf("Port = %d, want 9000", cfg.Port)
+	}
+	if _, err := ValidateConfig(cfg); err != nil {
+		t.Fatalf("ValidateConfig: %v", err)
+	}
+}
+
+func TestValidateConfigUnixSocketOnly(t *testing.T) {
+	cfg := &Config{Listen: []string{"unix:/run/pp.sock"}}
+	cfg.SanitizeListen()
+	if _, err := ValidateConfig(cfg); err != nil {
+		t.Fatalf("ValidateConfig: %v", err)
+	}
+	if got := (&Config{Port: 8317}).ListenAddresses(); len(got) != 1 || got[0] != ":8317" {
+		t.Fatalf("ListenAddresses = %v", got)
+	}
+}
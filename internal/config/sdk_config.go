@@ -40,6 +40,11 @@ type SDKConfig struct {
 	// Default is false (disabled).
 	PassthroughHeaders bool `yaml:"passthrough-headers" json:"passthrough-headers"`
 
+	// PassthroughHeadersAllowlist restricts forwarded upstream response headers to the listed
+	// names (case-insensitive; a trailing "*" matches by prefix, e.g. "anthropic-ratelimit-*").
+	// When set, only these headers are forwarded, even if PassthroughHeaders is false.
+	PassthroughHeadersAllowlist []string `yaml:"passthrough-headers-allowlist,omitempty" json:"passthrough-headers-allowlist,omitempty"`
+
 	// Access holds request authentication provider configuration.
 	Access AccessConfig `yaml:"auth,omitempty" json:"auth,omitempty"`
 
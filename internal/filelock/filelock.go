@@ -0,0 +1,119 @@
+// Package filelock provides advisory cross-process locking for config and auth writes.
+//
+// Locks are implemented as sidecar "<path>.lock" files created with O_EXCL, which behaves
+// the same on Windows, macOS and Linux. The lock file records the owner PID so contention
+// can be reported as "locked by PID N" instead of silently corrupting the target file.
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds how long Lock waits for a competing writer.
+	DefaultTimeout = 5 * time.Second
+	// staleAfter is the age after which an abandoned lock file is reclaimed.
+	// Config and token writes complete in milliseconds, so this is generous.
+	staleAfter = 30 * time.Second
+	retryDelay = 25 * time.Millisecond
+)
+
+// ErrLocked is matched by errors.Is when a lock could not be acquired in time.
+var ErrLocked = errors.New("file is locked")
+
+// LockedError reports the path and owner of a lock that could not be acquired.
+type LockedError struct {
+	Path string
+	PID  int
+}
+
+func (e *LockedError) Error() string {
+	if e.PID > 0 {
+		return fmt.Sprintf("%s is locked by PID %d", e.Path, e.PID)
+	}
+	return fmt.Sprintf("%s is locked by another process", e.Path)
+}
+
+// Is allows errors.Is(err, ErrLocked).
+func (e *LockedError) Is(target error) bool { return target == ErrLocked }
+
+// Lock acquires the advisory lock for path, waiting up to DefaultTimeout.
+// The returned function releases the lock and is safe to call more than once.
+func Lock(path string) (func(), error) {
+	return LockTimeout(path, DefaultTimeout)
+}
+
+// LockTimeout acquires the advisory lock for path, waiting up to timeout.
+func LockTimeout(path string, timeout time.Duration) (func(), error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return func() {}, nil
+	}
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, _ = f.WriteString(strconv.Itoa(os.Getpid()))
+			_ = f.Close()
+			released := false
+			return func() {
+				if released {
+					return
+				}
+				released = true
+				_ = os.Remove(lockPath)
+			}, nil
+		}
+		if !os.IsExist(err) {
+			// Directory missing or not writable: locking is advisory, so let the write proceed
+			// and surface the real error from the caller's own file operations.
+			return func() {}, nil
+		}
+		if reclaimStale(lockPath) {
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, &LockedError{Path: path, PID: ownerPID(lockPath)}
+		}
+		time.Sleep(retryDelay)
+	}
+}
+
+// WithLock runs fn while holding the advisory lock for path.
+func WithLock(path string, fn func() error) error {
+	unlock, err := Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
+
+func reclaimStale(lockPath string) bool {
+	info, err := os.Stat(lockPath)
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+	if time.Since(info.ModTime()) < staleAfter {
+		return false
+	}
+	return os.Remove(lockPath) == nil
+}
+
+func ownerPID(lockPath string) int {
+	raw, err := os.ReadFile(lockPath)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
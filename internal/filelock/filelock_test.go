@@ -0,0 +1,65 @@
+package filelock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLockTimeoutReportsOwnerPID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	defer unlock()
+
+	_, err = LockTimeout(path, 50*time.Millisecond)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked, got %v", err)
+	}
+	var locked *LockedError
+	if !errors.As(err, &locked) || locked.PID != os.Getpid() {
+		t.Fatalf("expected owner PID %d, got %v", os.Getpid(), err)
+	}
+}
+
+func TestLockReleaseAllowsReacquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth.json")
+
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	unlock()
+	unlock()
+
+	if err = WithLock(path, func() error { return nil }); err != nil {
+		t.Fatalf("WithLock() after release error = %v", err)
+	}
+	if _, errStat := os.Stat(path + ".lock"); !os.IsNotExist(errStat) {
+		t.Fatalf("expected lock file to be removed, stat err = %v", errStat)
+	}
+}
+
+func TestLockReclaimsStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	lockPath := path + ".lock"
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(999999)), 0o600); err != nil {
+		t.Fatalf("write stale lock: %v", err)
+	}
+	old := time.Now().Add(-2 * staleAfter)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	unlock, err := LockTimeout(path, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected stale lock to be reclaimed, got %v", err)
+	}
+	unlock()
+}
@@ -0,0 +1,187 @@
+// Package inflight tracks requests that are currently executing against upstream providers.
+//
+// Each API request registers an entry when its execution context is created and removes it
+// when the handler finishes. Entries record the requested model, the account that served the
+// request and how many bytes have been streamed back, and can be cancelled by ID so an
+// operator can stop an agent that is stuck in a runaway loop.
+package inflight
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Request is a point-in-time snapshot of an executing request.
+type Request struct {
+	ID            string    `json:"id"`
+	Method        string    `json:"method,omitempty"`
+	Path          string    `json:"path,omitempty"`
+	Model         string    `json:"model,omitempty"`
+	Providers     []string  `json:"providers,omitempty"`
+	AuthID        string    `json:"auth_id,omitempty"`
+	Stream        bool      `json:"stream"`
+	StartedAt     time.Time `json:"started_at"`
+	ElapsedMs     int64     `json:"elapsed_ms"`
+	BytesStreamed int64     `json:"bytes_streamed"`
+}
+
+// Entry is the live record of one executing request.
+type Entry struct {
+	id        string
+	method    string
+	path      string
+	startedAt time.Time
+	cancel    context.CancelFunc
+
+	mu        sync.Mutex
+	model     string
+	providers []string
+	authID    string
+	stream    bool
+
+	bytes atomic.Int64
+	done  atomic.Bool
+}
+
+type entryContextKey struct{}
+
+var (
+	mu      sync.RWMutex
+	entries = map[string]*Entry{}
+	seq     atomic.Uint64
+)
+
+// Register records a new executing request. id is used as-is when non-empty and not
+// already taken; otherwise a sequential ID is generated. cancel is invoked by Cancel.
+func Register(id, method, path string, cancel context.CancelFunc) *Entry {
+	e := &Entry{method: method, path: path, startedAt: time.Now(), cancel: cancel}
+	mu.Lock()
+	if _, taken := entries[id]; id == "" || taken {
+		id = "req-" + strconv.FormatUint(seq.Add(1), 10)
+	}
+	e.id = id
+	entries[id] = e
+	mu.Unlock()
+	return e
+}
+
+// WithEntry returns a copy of ctx carrying e.
+func WithEntry(ctx context.Context, e *Entry) context.Context {
+	if ctx == nil || e == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, entryContextKey{}, e)
+}
+
+// FromContext returns the entry stored in ctx, or nil.
+func FromContext(ctx context.Context) *Entry {
+	if ctx == nil {
+		return nil
+	}
+	e, _ := ctx.Value(entryContextKey{}).(*Entry)
+	return e
+}
+
+// ID returns the entry identifier.
+func (e *Entry) ID() string {
+	if e == nil {
+		return ""
+	}
+	return e.id
+}
+
+// SetModel records the model and candidate providers for the request.
+func (e *Entry) SetModel(model string, providers []string, stream bool) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	e.model = model
+	e.providers = append([]string(nil), providers...)
+	e.stream = stream
+	e.mu.Unlock()
+}
+
+// SetAuth records the account selected to serve the request.
+func (e *Entry) SetAuth(authID string) {
+	if e == nil || authID == "" {
+		return
+	}
+	e.mu.Lock()
+	e.authID = authID
+	e.mu.Unlock()
+}
+
+// AddBytes adds n to the number of bytes streamed back to the client.
+func (e *Entry) AddBytes(n int) {
+	if e == nil || n <= 0 {
+		return
+	}
+	e.bytes.Add(int64(n))
+}
+
+// Done removes the entry from the registry. It is safe to call more than once.
+func (e *Entry) Done() {
+	if e == nil || !e.done.CompareAndSwap(false, true) {
+		return
+	}
+	mu.Lock()
+	if entries[e.id] == e {
+		delete(entries, e.id)
+	}
+	mu.Unlock()
+}
+
+func (e *Entry) snapshot(now time.Time) Request {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return Request{
+		ID:            e.id,
+		Method:        e.method,
+		Path:          e.path,
+		Model:         e.model,
+		Providers:     append([]string(nil), e.providers...),
+		AuthID:        e.authID,
+		Stream:        e.stream,
+		StartedAt:     e.startedAt,
+		ElapsedMs:     now.Sub(e.startedAt).Milliseconds(),
+		BytesStreamed: e.bytes.Load(),
+	}
+}
+
+// List returns the executing requests, oldest first.
+func List() []Request {
+	now := time.Now()
+	mu.RLock()
+	out := make([]Request, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, e.snapshot(now))
+	}
+	mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].StartedAt.Equal(out[j].StartedAt) {
+			return out[i].ID < out[j].ID
+		}
+		return out[i].StartedAt.Before(out[j].StartedAt)
+	})
+	return out
+}
+
+// Cancel aborts the request with the given ID. It reports whether the request was found.
+func Cancel(id string) bool {
+	mu.RLock()
+	e := entries[id]
+	mu.RUnlock()
+	if e == nil {
+		return false
+	}
+	if e.cancel != nil {
+		e.cancel()
+	}
+	e.Done()
+	return true
+}
@@ -0,0 +1,62 @@
+package inflight
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRegisterListCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	e := Register("abc", "POST", "/v1/messages", cancel)
+	defer e.Done()
+	e.SetModel("claude-sonnet", []string{"claude"}, true)
+	e.SetAuth("auth-1")
+	e.AddBytes(10)
+	e.AddBytes(5)
+
+	if got := FromContext(WithEntry(context.Background(), e)); got != e {
+		t.Fatalf("FromContext returned %v, want entry", got)
+	}
+
+	var found *Request
+	for _, r := range List() {
+		if r.ID == "abc" {
+			r := r
+			found = &r
+		}
+	}
+	if found == nil {
+		t.Fatal("registered request not listed")
+	}
+	if found.Model != "claude-sonnet" || found.AuthID != "auth-1" || found.BytesStreamed != 15 || !found.Stream {
+		t.Fatalf("unexpected snapshot: %+v", *found)
+	}
+
+	if !Cancel("abc") {
+		t.Fatal("Cancel returned false for registered request")
+	}
+	if ctx.Err() == nil {
+		t.Fatal("context not cancelled")
+	}
+	if Cancel("abc") {
+		t.Fatal("Cancel returned true after request was removed")
+	}
+}
+
+func TestRegisterGeneratesUniqueIDs(t *testing.T) {
+	a := Register("dup", "", "", nil)
+	b := Register("dup", "", "", nil)
+	c := Register("", "", "", nil)
+	defer a.Done()
+	defer b.Done()
+	defer c.Done()
+	if a.ID() != "dup" || b.ID() == "dup" || c.ID() == "" || b.ID() == c.ID() {
+		t.Fatalf("unexpected ids: %q %q %q", a.ID(), b.ID(), c.ID())
+	}
+	b.Done()
+	for _, r := range List() {
+		if r.ID == b.ID() {
+			t.Fatal("done entry still listed")
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Package loadshed rejects low-priority requests while the host is short on resources.
+//
+// A background sampler records system CPU and memory utilisation (Linux only) and the
+// proxy's own memory footprint. While any configured watermark is exceeded, requests
+// classified as low priority (batch embedding calls or requests explicitly marked low
+// priority) are refused so interactive traffic keeps flowing.
+package loadshed
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PriorityHeader lets clients mark a request as low priority ("low" or "batch").
+const PriorityHeader = "X-ProxyPilot-Priority"
+
+// Settings controls load shedding.
+type Settings struct {
+	Enabled            bool
+	MaxCPUPercent      float64
+	MaxMemoryPercent   float64
+	MaxProcessMemoryMB int
+	SampleInterval     time.Duration
+	RetryAfter         time.Duration
+	LowPriorityPaths   []string
+}
+
+// Sample is one resource usage measurement. Negative percentages mean unavailable.
+type Sample struct {
+	CPUPercent      float64
+	MemoryPercent   float64
+	ProcessMemoryMB float64
+}
+
+// Status is a point-in-time snapshot of load shedding state.
+type Status struct {
+	Enabled            bool      `json:"enabled"`
+	Overloaded         bool      `json:"overloaded"`
+	Reason             string    `json:"reason,omitempty"`
+	CPUPercent         float64   `json:"cpu_percent"`
+	MemoryPercent      float64   `json:"memory_percent"`
+	ProcessMemoryMB    float64   `json:"process_memory_mb"`
+	MaxCPUPercent      float64   `json:"max_cpu_percent,omitempty"`
+	MaxMemoryPercent   float64   `json:"max_memory_percent,omitempty"`
+	MaxProcessMemoryMB int       `json:"max_process_memory_mb,omitempty"`
+	LastSample         time.Time `json:"last_sample,omitempty"`
+	Shed               int64     `json:"shed"`
+}
+
+var (
+	mu       sync.RWMutex
+	settings Settings
+	status   Status
+	stopCh   chan struct{}
+
+	overloaded atomic.Bool
+	shed       atomic.Int64
+
+	// sampleFn is replaced in tests.
+	sampleFn = takeSample
+)
+
+// Apply replaces the settings and restarts the sampler.
+func Apply(s Settings) {
+	Stop()
+	if s.SampleInterval <= 0 {
+		s.SampleInterval = 5 * time.Second
+	}
+	if s.RetryAfter <= 0 {
+		s.RetryAfter = 30 * time.Second
+	}
+	s.LowPriorityPaths = append([]string(nil), s.LowPriorityPaths...)
+	enabled := s.Enabled && (s.MaxCPUPercent > 0 || s.MaxMemoryPercent > 0 || s.MaxProcessMemoryMB > 0)
+
+	mu.Lock()
+	settings = s
+	status = Status{
+		Enabled:            enabled,
+		MaxCPUPercent:      s.MaxCPUPercent,
+		MaxMemoryPercent:   s.MaxMemoryPercent,
+		MaxProcessMemoryMB: s.MaxProcessMemoryMB,
+	}
+	if enabled {
+		stopCh = make(chan struct{})
+		go sampleLoop(stopCh, s.SampleInterval)
+	}
+	mu.Unlock()
+	if s.Enabled && !enabled {
+		log.Warn("load shedding: enabled without any watermark; ignoring")
+	}
+}
+
+// Stop halts the sampler and clears the overloaded state.
+func Stop() {
+	mu.Lock()
+	if stopCh != nil {
+		close(stopCh)
+		stopCh = nil
+	}
+	status.Enabled = false
+	status.Overloaded = false
+	status.Reason = ""
+	mu.Unlock()
+	overloaded.Store(false)
+}
+
+// CurrentStatus returns the latest sample and shedding state.
+func CurrentStatus() Status {
+	mu.RLock()
+	out := status
+	mu.RUnlock()
+	out.Shed = shed.Load()
+	return out
+}
+
+// IsLowPriority reports whether a request with the given path and priority header
+// value may be shed.
+func IsLowPriority(path, priority string) bool {
+	switch strings.ToLower(strings.TrimSpace(priority)) {
+	case "low", "batch":
+		return true
+	case "high", "interactive":
+		return false
+	}
+	mu.RLock()
+	patterns := settings.LowPriorityPaths
+	mu.RUnlock()
+	for _, p := range patterns {
+		if p != "" && strings.Contains(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShouldShed reports whether a low-priority request should be refused now, returning
+// the Retry-After delay and the reason when it should.
+func ShouldShed() (time.Duration, string, bool) {
+	if !overloaded.Load() {
+		return 0, "", false
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	return settings.RetryAfter, status.Reason, true
+}
+
+// RecordShed counts a refused request and logs it.
+func RecordShed(method, path, reason string) {
+	n := shed.Add(1)
+	log.Warnf("load shedding: refused %s %s (%s); %d request(s) shed so far", method, path, reason, n)
+}
+
+func sampleLoop(stop chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	evaluate(sampleFn())
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			evaluate(sampleFn())
+		}
+	}
+}
+
+// evaluate records a sample and updates the overloaded state, logging transitions.
+func evaluate(sample Sample) {
+	mu.Lock()
+	if !status.Enabled {
+		mu.Unlock()
+		return
+	}
+	reason := exceeded(settings, sample)
+	wasOverloaded := status.Overloaded
+	status.CPUPercent = sample.CPUPercent
+	status.MemoryPercent = sample.MemoryPercent
+	status.ProcessMemoryMB = sample.ProcessMemoryMB
+	status.LastSample = time.Now()
+	status.Overloaded = reason != ""
+	status.Reason = reason
+	mu.Unlock()
+	overloaded.Store(reason != "")
+
+	switch {
+	case reason != "" && !wasOverloaded:
+		log.Warnf("load shedding: %s; shedding low-priority requests", reason)
+	case reason == "" && wasOverloaded:
+		log.Info("load shedding: resource usage back below watermarks; accepting all requests")
+	}
+}
+
+func exceeded(s Settings, sample Sample) string {
+	switch {
+	case s.MaxCPUPercent > 0 && sample.CPUPercent >= s.MaxCPUPercent:
+		return fmt.Sprintf("cpu %.1f%% >= %.1f%%", sample.CPUPercent, s.MaxCPUPercent)
+	case s.MaxMemoryPercent > 0 && sample.MemoryPercent >= s.MaxMemoryPercent:
+		return fmt.Sprintf("memory %.1f%% >= %.1f%%", sample.MemoryPercent, s.MaxMemoryPercent)
+	case s.MaxProcessMemoryMB > 0 && sample.ProcessMemoryMB >= float64(s.MaxProcessMemoryMB):
+		return fmt.Sprintf("process memory %.0fMB >= %dMB", sample.ProcessMemoryMB, s.MaxProcessMemoryMB)
+	}
+	return ""
+}
+
+func takeSample() Sample {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return Sample{
+		CPUPercent:      systemCPUPercent(),
+		MemoryPercent:   systemMemoryPercent(),
+		ProcessMemoryMB: float64(ms.Sys) / (1024 * 1024),
+	}
+}
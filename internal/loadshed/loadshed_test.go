@@ -0,0 +1,76 @@
+package loadshed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateTogglesShedding(t *testing.T) {
+	samples := make(chan Sample, 1)
+	samples <- Sample{CPUPercent: 10, MemoryPercent: 20, ProcessMemoryMB: 50}
+	sampleFn = func() Sample { return <-samples }
+	t.Cleanup(func() {
+		Stop()
+		sampleFn = takeSample
+	})
+
+	Apply(Settings{Enabled: true, MaxMemoryPercent: 90, SampleInterval: time.Hour, RetryAfter: 15 * time.Second})
+	waitFor(t, func() bool { return !CurrentStatus().LastSample.IsZero() })
+	if _, _, shed := ShouldShed(); shed {
+		t.Fatal("shedding below watermark")
+	}
+
+	evaluate(Sample{CPUPercent: 10, MemoryPercent: 95, ProcessMemoryMB: 50})
+	retryAfter, reason, shed := ShouldShed()
+	if !shed || retryAfter != 15*time.Second || reason == "" {
+		t.Fatalf("ShouldShed = %v %q %v, want shedding with 15s retry", retryAfter, reason, shed)
+	}
+
+	evaluate(Sample{CPUPercent: 10, MemoryPercent: 40, ProcessMemoryMB: 50})
+	if _, _, shed = ShouldShed(); shed {
+		t.Fatal("still shedding after recovery")
+	}
+}
+
+func TestApplyWithoutWatermarkStaysDisabled(t *testing.T) {
+	t.Cleanup(Stop)
+	Apply(Settings{Enabled: true})
+	if CurrentStatus().Enabled {
+		t.Fatal("enabled without any watermark")
+	}
+	evaluate(Sample{MemoryPercent: 100})
+	if _, _, shed := ShouldShed(); shed {
+		t.Fatal("shedding while disabled")
+	}
+}
+
+func TestIsLowPriority(t *testing.T) {
+	t.Cleanup(Stop)
+	Apply(Settings{LowPriorityPaths: []string{":batchEmbedContents", "/embeddings"}})
+	cases := []struct {
+		path, priority string
+		want           bool
+	}{
+		{path: "/v1beta/models/text-embedding-004:batchEmbedContents", want: true},
+		{path: "/v1/embeddings", priority: "high", want: false},
+		{path: "/v1/chat/completions", want: false},
+		{path: "/v1/chat/completions", priority: "Batch", want: true},
+		{path: "/v1/messages", priority: "low", want: true},
+	}
+	for _, tc := range cases {
+		if got := IsLowPriority(tc.path, tc.priority); got != tc.want {
+			t.Errorf("IsLowPriority(%q, %q) = %v, want %v", tc.path, tc.priority, got, tc.want)
+		}
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,88 @@
+//go:build linux
+
+package loadshed
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	cpuMu        sync.Mutex
+	cpuPrevIdle  uint64
+	cpuPrevTotal uint64
+)
+
+// systemCPUPercent returns CPU utilisation since the previous call, from /proc/stat.
+// The first call reports utilisation since boot.
+func systemCPUPercent() float64 {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return -1
+	}
+	defer func() { _ = f.Close() }()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return -1
+	}
+	fields := strings.Fields(scanner.Text())
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return -1
+	}
+	var idle, total uint64
+	for i, field := range fields[1:] {
+		v, errParse := strconv.ParseUint(field, 10, 64)
+		if errParse != nil {
+			return -1
+		}
+		total += v
+		// idle and iowait columns
+		if i == 3 || i == 4 {
+			idle += v
+		}
+	}
+	cpuMu.Lock()
+	defer cpuMu.Unlock()
+	deltaTotal := total - cpuPrevTotal
+	deltaIdle := idle - cpuPrevIdle
+	cpuPrevIdle, cpuPrevTotal = idle, total
+	if deltaTotal == 0 {
+		return 0
+	}
+	return 100 * float64(deltaTotal-deltaIdle) / float64(deltaTotal)
+}
+
+// systemMemoryPercent returns the share of memory not available to new allocations,
+// from /proc/meminfo.
+func systemMemoryPercent() float64 {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return -1
+	}
+	defer func() { _ = f.Close() }()
+	var memTotal, memAvailable uint64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		v, errParse := strconv.ParseUint(fields[1], 10, 64)
+		if errParse != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			memTotal = v
+		case "MemAvailable:":
+			memAvailable = v
+		}
+	}
+	if memTotal == 0 || memAvailable > memTotal {
+		return -1
+	}
+	return 100 * float64(memTotal-memAvailable) / float64(memTotal)
+}
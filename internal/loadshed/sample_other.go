@@ -0,0 +1,9 @@
+//go:build !linux
+
+package loadshed
+
+// systemCPUPercent is not sampled on this platform.
+func systemCPUPercent() float64 { return -1 }
+
+// systemMemoryPercent is not sampled on this platform.
+func systemMemoryPercent() float64 { return -1 }
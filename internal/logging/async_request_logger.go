@@ -0,0 +1,186 @@
+package logging
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/interfaces"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultAsyncRequestLogBufferSize is the queue length used when none is configured.
+const DefaultAsyncRequestLogBufferSize = 256
+
+// requestLogWriter is implemented by loggers that support forced error logging.
+type requestLogWriter interface {
+	RequestLogger
+	LogRequestWithOptions(url, method string, requestHeaders map[string][]string, body []byte, statusCode int, responseHeaders map[string][]string, response, websocketTimeline, apiRequest, apiResponse, apiWebsocketTimeline []byte, apiResponseErrors []*interfaces.ErrorMessage, force bool, requestID string, requestTimestamp, apiResponseTimestamp time.Time) error
+}
+
+// asyncLogEntry captures the arguments of one LogRequestWithOptions call.
+type asyncLogEntry struct {
+	url                  string
+	method               string
+	requestHeaders       map[string][]string
+	body                 []byte
+	statusCode           int
+	responseHeaders      map[string][]string
+	response             []byte
+	websocketTimeline    []byte
+	apiRequest           []byte
+	apiResponse          []byte
+	apiWebsocketTimeline []byte
+	apiResponseErrors    []*interfaces.ErrorMessage
+	force                bool
+	requestID            string
+	requestTimestamp     time.Time
+	apiResponseTimestamp time.Time
+}
+
+// AsyncRequestLogger moves non-streaming request log writes off the request path.
+//
+// Entries are queued in a bounded buffer and written by a single background worker.
+// When the buffer is full the oldest queued entry is dropped and counted, so a slow
+// disk never blocks responses. Streaming logs are delegated unchanged since their
+// chunks are already written asynchronously. Close drains the queue.
+type AsyncRequestLogger struct {
+	inner requestLogWriter
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan *asyncLogEntry
+	done   chan struct{}
+
+	dropped atomic.Uint64
+}
+
+// NewAsyncRequestLogger wraps inner with a queue of bufferSize entries.
+// A non-positive bufferSize uses DefaultAsyncRequestLogBufferSize.
+func NewAsyncRequestLogger(inner *FileRequestLogger, bufferSize int) *AsyncRequestLogger {
+	return newAsyncRequestLogger(inner, bufferSize)
+}
+
+func newAsyncRequestLogger(inner requestLogWriter, bufferSize int) *AsyncRequestLogger {
+	if bufferSize <= 0 {
+		bufferSize = DefaultAsyncRequestLogBufferSize
+	}
+	l := &AsyncRequestLogger{
+		inner: inner,
+		queue: make(chan *asyncLogEntry, bufferSize),
+		done:  make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// LogRequest queues a complete non-streaming request/response cycle for writing.
+func (l *AsyncRequestLogger) LogRequest(url, method string, requestHeaders map[string][]string, body []byte, statusCode int, responseHeaders map[string][]string, response, websocketTimeline, apiRequest, apiResponse, apiWebsocketTimeline []byte, apiResponseErrors []*interfaces.ErrorMessage, requestID string, requestTimestamp, apiResponseTimestamp time.Time) error {
+	return l.LogRequestWithOptions(url, method, requestHeaders, body, statusCode, responseHeaders, response, websocketTimeline, apiRequest, apiResponse, apiWebsocketTimeline, apiResponseErrors, false, requestID, requestTimestamp, apiResponseTimestamp)
+}
+
+// LogRequestWithOptions queues a request log, optionally forced when request logging is disabled.
+// After Close the entry is written synchronously.
+func (l *AsyncRequestLogger) LogRequestWithOptions(url, method string, requestHeaders map[string][]string, body []byte, statusCode int, responseHeaders map[string][]string, response, websocketTimeline, apiRequest, apiResponse, apiWebsocketTimeline []byte, apiResponseErrors []*interfaces.ErrorMessage, force bool, requestID string, requestTimestamp, apiResponseTimestamp time.Time) error {
+	if !l.inner.IsEnabled() && !force {
+		return nil
+	}
+	entry := &asyncLogEntry{
+		url:                  url,
+		method:               method,
+		requestHeaders:       http.Header(requestHeaders).Clone(),
+		body:                 body,
+		statusCode:           statusCode,
+		responseHeaders:      http.Header(responseHeaders).Clone(),
+		response:             response,
+		websocketTimeline:    websocketTimeline,
+		apiRequest:           apiRequest,
+		apiResponse:          apiResponse,
+		apiWebsocketTimeline: apiWebsocketTimeline,
+		apiResponseErrors:    apiResponseErrors,
+		force:                force,
+		requestID:            requestID,
+		requestTimestamp:     requestTimestamp,
+		apiResponseTimestamp: apiResponseTimestamp,
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return l.write(entry)
+	}
+	for {
+		select {
+		case l.queue <- entry:
+			return nil
+		default:
+		}
+		// Buffer full: drop the oldest entry to make room.
+		select {
+		case <-l.queue:
+			if n := l.dropped.Add(1); n == 1 || n%100 == 0 {
+				log.Warnf("request log buffer full; dropped %d entries so far", n)
+			}
+		default:
+		}
+	}
+}
+
+// LogStreamingRequest delegates to the wrapped logger.
+func (l *AsyncRequestLogger) LogStreamingRequest(url, method string, headers map[string][]string, body []byte, requestID string) (StreamingLogWriter, error) {
+	return l.inner.LogStreamingRequest(url, method, headers, body, requestID)
+}
+
+// IsEnabled returns whether request logging is currently enabled.
+func (l *AsyncRequestLogger) IsEnabled() bool {
+	return l.inner.IsEnabled()
+}
+
+// SetEnabled updates the request logging enabled state.
+func (l *AsyncRequestLogger) SetEnabled(enabled bool) {
+	if setter, ok := l.inner.(interface{ SetEnabled(bool) }); ok {
+		setter.SetEnabled(enabled)
+	}
+}
+
+// SetErrorLogsMaxFiles updates the maximum number of error log files to retain.
+func (l *AsyncRequestLogger) SetErrorLogsMaxFiles(maxFiles int) {
+	if setter, ok := l.inner.(interface{ SetErrorLogsMaxFiles(int) }); ok {
+		setter.SetErrorLogsMaxFiles(maxFiles)
+	}
+}
+
+// Dropped returns how many queued entries were discarded because the buffer was full.
+func (l *AsyncRequestLogger) Dropped() uint64 {
+	return l.dropped.Load()
+}
+
+// Close stops accepting queued entries and waits until the queue is written.
+// It is safe to call more than once.
+func (l *AsyncRequestLogger) Close() error {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.queue)
+	}
+	l.mu.Unlock()
+	<-l.done
+	if n := l.dropped.Load(); n > 0 {
+		log.Warnf("request log buffer dropped %d entries in total", n)
+	}
+	return nil
+}
+
+func (l *AsyncRequestLogger) run() {
+	defer close(l.done)
+	for entry := range l.queue {
+		if err := l.write(entry); err != nil {
+			log.Errorf("failed to write request log: %v", err)
+		}
+	}
+}
+
+func (l *AsyncRequestLogger) write(e *asyncLogEntry) error {
+	return l.inner.LogRequestWithOptions(e.url, e.method, e.requestHeaders, e.body, e.statusCode, e.responseHeaders, e.response, e.websocketTimeline, e.apiRequest, e.apiResponse, e.apiWebsocketTimeline, e.apiResponseErrors, e.force, e.requestID, e.requestTimestamp, e.apiResponseTimestamp)
+}
@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/interfaces"
+)
+
+// blockingLogWriter records request IDs and blocks writes until released.
+type blockingLogWriter struct {
+	FileRequestLogger
+	release chan struct{}
+	started chan struct{}
+	once    sync.Once
+
+	mu      sync.Mutex
+	written []string
+}
+
+func (b *blockingLogWriter) IsEnabled() bool { return true }
+
+func (b *blockingLogWriter) LogRequestWithOptions(_, _ string, _ map[string][]string, _ []byte, _ int, _ map[string][]string, _, _, _, _, _ []byte, _ []*interfaces.ErrorMessage, _ bool, requestID string, _, _ time.Time) error {
+	b.once.Do(func() { close(b.started) })
+	<-b.release
+	b.mu.Lock()
+	b.written = append(b.written, requestID)
+	b.mu.Unlock()
+	return nil
+}
+
+func logID(l *AsyncRequestLogger, id string) {
+	_ = l.LogRequest("/v1/chat/completions", "POST", nil, nil, 200, nil, nil, nil, nil, nil, nil, nil, id, time.Now(), time.Now())
+}
+
+func TestAsyncRequestLoggerDropsOldestWhenFull(t *testing.T) {
+	inner := &blockingLogWriter{release: make(chan struct{}), started: make(chan struct{})}
+	l := newAsyncRequestLogger(inner, 2)
+
+	logID(l, "a")
+	<-inner.started // worker holds "a"; the queue is empty again
+	logID(l, "b")
+	logID(l, "c")
+	logID(l, "d") // drops "b"
+
+	if got := l.Dropped(); got != 1 {
+		t.Fatalf("Dropped = %d, want 1", got)
+	}
+	close(inner.release)
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	want := []string{"a", "c", "d"}
+	if len(inner.written) != len(want) {
+		t.Fatalf("written = %v, want %v", inner.written, want)
+	}
+	for i := range want {
+		if inner.written[i] != want[i] {
+			t.Fatalf("written = %v, want %v", inner.written, want)
+		}
+	}
+}
+
+func TestAsyncRequestLoggerFlushesOnClose(t *testing.T) {
+	dir := t.TempDir()
+	l := NewAsyncRequestLogger(NewFileRequestLogger(true, dir, "", 0), 16)
+	for _, id := range []string{"r1", "r2", "r3"} {
+		logID(l, id)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read logs dir: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("log files = %d, want 3", len(entries))
+	}
+
+	// Writes after Close go straight to disk.
+	logID(l, "r4")
+	entries, _ = os.ReadDir(dir)
+	if len(entries) != 4 {
+		t.Fatalf("log files after close = %d, want 4", len(entries))
+	}
+}
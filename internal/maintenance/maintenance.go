@@ -0,0 +1,169 @@
+// Package maintenance tracks recurring per-account windows during which credentials are
+// kept out of routing.
+//
+// Each window pairs an account selector with a cron-style start schedule and a duration,
+// e.g. "weekdays from 09:00 for 8 hours" while the owner of a subscription uses it
+// interactively. The auth manager consults Blocked when picking credentials, so accounts
+// drop out of rotation when a window opens and return automatically when it closes.
+package maintenance
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Window is one configured maintenance window.
+type Window struct {
+	// Auth matches auth IDs, file names, labels or account emails. Wildcards are allowed.
+	Auth string
+	// Schedule is a five-field cron expression for the window start times.
+	Schedule string
+	// Duration is how long each window lasts.
+	Duration time.Duration
+	// Timezone is the IANA zone name used for Schedule; empty means local time.
+	Timezone string
+}
+
+// WindowStatus is a point-in-time snapshot of one window.
+type WindowStatus struct {
+	Auth            string    `json:"auth"`
+	Schedule        string    `json:"schedule"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Timezone        string    `json:"timezone,omitempty"`
+	Active          bool      `json:"active"`
+	ActiveUntil     time.Time `json:"active_until,omitempty"`
+	NextStart       time.Time `json:"next_start,omitempty"`
+}
+
+type compiledWindow struct {
+	Window
+	pattern  string
+	schedule *schedule
+	location *time.Location
+}
+
+var (
+	mu      sync.RWMutex
+	windows []compiledWindow
+)
+
+func compile(w Window) (compiledWindow, error) {
+	c := compiledWindow{Window: w, pattern: strings.ToLower(strings.TrimSpace(w.Auth))}
+	if c.pattern == "" {
+		return c, fmt.Errorf("maintenance window: auth is required")
+	}
+	if _, err := path.Match(c.pattern, ""); err != nil {
+		return c, fmt.Errorf("maintenance window for %q: invalid auth pattern: %w", w.Auth, err)
+	}
+	if w.Duration < time.Minute {
+		return c, fmt.Errorf("maintenance window for %q: duration must be at least one minute", w.Auth)
+	}
+	sched, err := parseSchedule(w.Schedule)
+	if err != nil {
+		return c, fmt.Errorf("maintenance window for %q: %w", w.Auth, err)
+	}
+	c.schedule = sched
+	c.location = time.Local
+	if tz := strings.TrimSpace(w.Timezone); tz != "" {
+		loc, errLoc := time.LoadLocation(tz)
+		if errLoc != nil {
+			return c, fmt.Errorf("maintenance window for %q: invalid timezone %q: %w", w.Auth, tz, errLoc)
+		}
+		c.location = loc
+	}
+	return c, nil
+}
+
+// Apply replaces the configured windows. Invalid windows are logged and skipped.
+func Apply(ws []Window) {
+	compiled := make([]compiledWindow, 0, len(ws))
+	for _, w := range ws {
+		c, err := compile(w)
+		if err != nil {
+			log.Warnf("ignoring %v", err)
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+	mu.Lock()
+	windows = compiled
+	mu.Unlock()
+	if len(compiled) > 0 {
+		log.Infof("maintenance windows configured: %d", len(compiled))
+	}
+}
+
+// Blocked reports whether any window matching one of keys is open at now, and when the
+// latest-ending such window closes. Keys are typically the auth ID, file name, label and
+// account email of a credential.
+func Blocked(keys []string, now time.Time) (bool, time.Time) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if len(windows) == 0 {
+		return false, time.Time{}
+	}
+	var until time.Time
+	for i := range windows {
+		w := &windows[i]
+		if !w.matches(keys) {
+			continue
+		}
+		if end := w.activeUntil(now); !end.IsZero() && end.After(until) {
+			until = end
+		}
+	}
+	return !until.IsZero(), until
+}
+
+// CurrentStatus returns a snapshot of the configured windows.
+func CurrentStatus() []WindowStatus {
+	now := time.Now()
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]WindowStatus, 0, len(windows))
+	for i := range windows {
+		w := &windows[i]
+		until := w.activeUntil(now)
+		out = append(out, WindowStatus{
+			Auth:            w.Auth,
+			Schedule:        w.Schedule,
+			DurationMinutes: int(w.Duration / time.Minute),
+			Timezone:        w.Timezone,
+			Active:          !until.IsZero(),
+			ActiveUntil:     until,
+			NextStart:       w.schedule.nextStart(now.In(w.location)),
+		})
+	}
+	return out
+}
+
+func (w *compiledWindow) matches(keys []string) bool {
+	for _, key := range keys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		if key == w.pattern {
+			return true
+		}
+		if ok, _ := path.Match(w.pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// activeUntil returns when the window open at now closes, or the zero time if it is closed.
+func (w *compiledWindow) activeUntil(now time.Time) time.Time {
+	local := now.In(w.location)
+	start := w.schedule.lastStart(local, local.Add(-w.Duration).Add(time.Nanosecond))
+	if start.IsZero() {
+		return time.Time{}
+	}
+	return start.Add(w.Duration)
+}
@@ -0,0 +1,78 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := parseSchedule(expr); err == nil {
+			t.Errorf("parseSchedule(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestBlockedDuringWeekdayWindow(t *testing.T) {
+	defer Apply(nil)
+	Apply([]Window{{Auth: "*@example.com", Schedule: "0 9 * * mon-fri", Duration: 8 * time.Hour, Timezone: "UTC"}})
+
+	keys := []string{"claude-1.json", "Me@Example.com"}
+	// Wednesday 2026-10-14.
+	cases := []struct {
+		at      time.Time
+		blocked bool
+	}{
+		{time.Date(2026, 10, 14, 8, 59, 0, 0, time.UTC), false},
+		{time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, 10, 14, 16, 59, 59, 0, time.UTC), true},
+		{time.Date(2026, 10, 14, 17, 0, 0, 0, time.UTC), false},
+		// Saturday.
+		{time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC), false},
+	}
+	for _, tc := range cases {
+		blocked, until := Blocked(keys, tc.at)
+		if blocked != tc.blocked {
+			t.Errorf("Blocked at %s = %v, want %v", tc.at, blocked, tc.blocked)
+		}
+		if blocked && !until.Equal(time.Date(2026, 10, 14, 17, 0, 0, 0, time.UTC)) {
+			t.Errorf("until = %s, want 17:00", until)
+		}
+	}
+	if blocked, _ := Blocked([]string{"other@elsewhere.com"}, time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)); blocked {
+		t.Error("non-matching auth blocked")
+	}
+}
+
+func TestWindowSpanningMidnightUsesTimezone(t *testing.T) {
+	defer Apply(nil)
+	Apply([]Window{{Auth: "night", Schedule: "0 22 * * *", Duration: 4 * time.Hour, Timezone: "America/New_York"}})
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("timezone database unavailable")
+	}
+	if blocked, _ := Blocked([]string{"night"}, time.Date(2026, 10, 15, 1, 30, 0, 0, ny)); !blocked {
+		t.Error("expected window open after midnight")
+	}
+	if blocked, _ := Blocked([]string{"night"}, time.Date(2026, 10, 15, 2, 0, 0, 0, ny)); blocked {
+		t.Error("expected window closed at 02:00")
+	}
+}
+
+func TestApplySkipsInvalidWindows(t *testing.T) {
+	defer Apply(nil)
+	Apply([]Window{
+		{Auth: "", Schedule: "* * * * *", Duration: time.Hour},
+		{Auth: "a", Schedule: "bad", Duration: time.Hour},
+		{Auth: "b", Schedule: "* * * * *", Duration: 0},
+		{Auth: "c", Schedule: "0 0 1 1 *", Duration: time.Hour, Timezone: "UTC"},
+	})
+	status := CurrentStatus()
+	if len(status) != 1 || status[0].Auth != "c" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+	if status[0].NextStart.IsZero() || status[0].NextStart.Month() != time.January || status[0].NextStart.Day() != 1 {
+		t.Fatalf("unexpected next start: %s", status[0].NextStart)
+	}
+}
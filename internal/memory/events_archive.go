@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Session event logs are append-only and only their tail is ever searched, so once
+// events.jsonl grows past the rotation threshold the older head is moved into
+// events.archive.jsonl.gz. Each rotation appends a new gzip member; readers that
+// use gzip.Reader (which handles multistream input) see one continuous JSONL stream.
+
+const (
+	sessionEventsFile        = "events.jsonl"
+	sessionEventsArchiveFile = "events.archive.jsonl.gz"
+
+	defaultEventsRotateBytes = 4 * 1024 * 1024
+	// eventsKeepBytes matches the tail window used by Search and ReadEventTail.
+	eventsKeepBytes = 2 * 1024 * 1024
+)
+
+var eventsArchiveMu sync.Mutex
+
+func eventsRotateBytes() int64 {
+	if v := strings.TrimSpace(os.Getenv("CLIPROXY_MEMORY_EVENTS_ROTATE_BYTES")); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	}
+	return defaultEventsRotateBytes
+}
+
+// archiveSessionEvents compresses the head of events.jsonl into the session archive
+// when the file exceeds the rotation threshold. A threshold <= 0 disables archiving.
+func archiveSessionEvents(dir string) error {
+	rotateAt := eventsRotateBytes()
+	if rotateAt <= 0 {
+		return nil
+	}
+	keep := int64(eventsKeepBytes)
+	if keep > rotateAt/2 {
+		keep = rotateAt / 2
+	}
+
+	eventsArchiveMu.Lock()
+	defer eventsArchiveMu.Unlock()
+
+	path := filepath.Join(dir, sessionEventsFile)
+	fi, err := os.Stat(path)
+	if err != nil || fi.Size() <= rotateAt {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	cut := int64(len(data)) - keep
+	if cut <= 0 {
+		return nil
+	}
+	// Split on a line boundary so both halves stay valid JSONL.
+	if i := bytes.IndexByte(data[cut:], '\n'); i >= 0 {
+		cut += int64(i) + 1
+	} else {
+		return nil
+	}
+
+	archivePath := filepath.Join(dir, sessionEventsArchiveFile)
+	af, err := os.OpenFile(archivePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(af)
+	_, errWrite := zw.Write(data[:cut])
+	errZip := zw.Close()
+	errClose := af.Close()
+	if err = errors.Join(errWrite, errZip, errClose); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data[cut:], 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadArchivedEvents returns up to limit of the most recent events that were moved
+// into the session's compressed archive, in chronological order.
+func (s *FileStore) ReadArchivedEvents(session string, limit int) ([]Event, error) {
+	if s == nil || s.BaseDir == "" {
+		return nil, errors.New("memory store not configured")
+	}
+	if session == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	f, err := os.Open(filepath.Join(s.sessionDir(session), sessionEventsArchiveFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Event{}, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return []Event{}, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = zr.Close() }()
+
+	ring := make([]Event, 0, limit)
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil || strings.TrimSpace(e.Text) == "" {
+			continue
+		}
+		if len(ring) == limit {
+			copy(ring, ring[1:])
+			ring = ring[:limit-1]
+		}
+		ring = append(ring, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return ring, err
+	}
+	return ring, nil
+}
@@ -17,6 +17,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/util"
 )
 
 type FileStore struct {
@@ -34,6 +36,18 @@ type searchCacheEntry struct {
 	maxSnips int
 }
 
+// DefaultBaseDir returns the memory directory: CLIPROXY_MEMORY_DIR when set, otherwise
+// .proxypilot/memory under the writable path.
+func DefaultBaseDir() string {
+	if v := strings.TrimSpace(os.Getenv("CLIPROXY_MEMORY_DIR")); v != "" {
+		return v
+	}
+	if w := util.WritablePath(); w != "" {
+		return filepath.Join(w, ".proxypilot", "memory")
+	}
+	return filepath.Join(".proxypilot", "memory")
+}
+
 func NewFileStore(baseDir string) *FileStore {
 	return &FileStore{
 		BaseDir: baseDir,
@@ -81,7 +95,11 @@ func (s *FileStore) Append(session string, events []Event) error {
 		_, _ = w.Write(b)
 		_, _ = w.WriteString("\n")
 	}
-	return w.Flush()
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_ = archiveSessionEvents(dir)
+	return nil
 }
 
 func (s *FileStore) Search(session string, query string, maxChars int, maxSnippets int) ([]string, error) {
@@ -19,6 +19,7 @@ type SessionInfo struct {
 	UpdatedAt        time.Time `json:"updated_at"`
 	SizeBytes        int64     `json:"size_bytes"`
 	EventsBytes      int64     `json:"events_bytes"`
+	ArchivedBytes    int64     `json:"archived_bytes,omitempty"`
 	HasSummary       bool      `json:"has_summary"`
 	HasTodo          bool      `json:"has_todo"`
 	HasPinned        bool      `json:"has_pinned"`
@@ -123,6 +124,8 @@ func (s *FileStore) getSessionInfoFromDir(session string, dir string) (SessionIn
 		case "events.jsonl":
 			eventsBytes = fi.Size()
 			_ = p
+		case sessionEventsArchiveFile:
+			info.ArchivedBytes = fi.Size()
 		}
 	}
 	info.UpdatedAt = latest
@@ -308,7 +311,7 @@ func (s *FileStore) PruneSemantic(maxAgeDays int, maxNamespaces int, maxBytesPer
 			continue
 		}
 		path := filepath.Join(semanticDir, e.Name())
-		fi, err := os.Stat(filepath.Join(path, "items.jsonl"))
+		fi, err := os.Stat(filepath.Join(path, semanticItemsFile))
 		if err != nil {
 			continue
 		}
@@ -342,16 +345,43 @@ func (s *FileStore) PruneSemantic(maxAgeDays int, maxNamespaces int, maxBytesPer
 			continue
 		}
 		if maxBytesPerNamespace > 0 {
-			itemsPath := filepath.Join(ns.path, "items.jsonl")
+			itemsPath := filepath.Join(ns.path, semanticItemsFile)
 			if trimmed, freed := trimJSONLFile(itemsPath, maxBytesPerNamespace); trimmed {
 				res.SemanticNamespacesTrimmed++
-				res.BytesFreed += freed
+				res.BytesFreed += freed + compactSemanticVectors(ns.path)
+				continue
 			}
 		}
+		semanticWriteMu.Lock()
+		migrateSemanticDir(ns.path)
+		semanticWriteMu.Unlock()
 	}
 	return res, nil
 }
 
+// compactSemanticVectors drops vectors no longer referenced after the index was trimmed
+// and returns the number of bytes reclaimed from vectors.bin.
+func compactSemanticVectors(dir string) int64 {
+	semanticWriteMu.Lock()
+	defer semanticWriteMu.Unlock()
+	vectorsPath := filepath.Join(dir, semanticVectorsFile)
+	before, err := os.Stat(vectorsPath)
+	if err != nil {
+		return 0
+	}
+	if err := rewriteSemanticDir(dir); err != nil {
+		return 0
+	}
+	var after int64
+	if fi, err := os.Stat(vectorsPath); err == nil {
+		after = fi.Size()
+	}
+	if freed := before.Size() - after; freed > 0 {
+		return freed
+	}
+	return 0
+}
+
 func trimJSONLFile(path string, maxBytes int64) (bool, int64) {
 	if maxBytes <= 0 {
 		return false, 0
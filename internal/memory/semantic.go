@@ -46,13 +46,28 @@ func (s *FileStore) AppendSemantic(namespace string, records []SemanticRecord) e
 		return err
 	}
 	_ = s.writeSemanticNamespace(dir, namespace)
-	path := filepath.Join(dir, "items.jsonl")
+
+	semanticWriteMu.Lock()
+	defer semanticWriteMu.Unlock()
+	migrateSemanticDir(dir)
+
+	path := filepath.Join(dir, semanticItemsFile)
 	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = f.Close() }()
 
+	var vectors *semanticVectorWriter
+	if enc := semanticVectorEncoding(); enc != vectorEncodingJSON {
+		vectors, err = openSemanticVectorWriter(filepath.Join(dir, semanticVectorsFile), enc)
+		if err != nil {
+			return err
+		}
+	}
+
+	var out bytes.Buffer
+
 	seen := make(map[string]struct{}, len(records))
 	for i := range records {
 		r := records[i]
@@ -73,14 +88,28 @@ func (s *FileStore) AppendSemantic(namespace string, records []SemanticRecord) e
 		if r.Norm <= 0 {
 			continue
 		}
-		b, err := json.Marshal(r)
+		rec := indexRecordFrom(r)
+		if vectors != nil {
+			if err := vectors.put(&rec); err != nil {
+				_ = vectors.Close()
+				return err
+			}
+		}
+		b, err := json.Marshal(rec)
 		if err != nil {
 			continue
 		}
-		_, _ = f.Write(b)
-		_, _ = f.WriteString("\n")
+		out.Write(b)
+		out.WriteByte('\n')
+	}
+	// Vectors must be on disk before the index lines that reference them.
+	if vectors != nil {
+		if err := vectors.Close(); err != nil {
+			return err
+		}
 	}
-	return nil
+	_, err = f.Write(out.Bytes())
+	return err
 }
 
 func (s *FileStore) SearchSemantic(namespace string, query []float32, maxChars int, maxSnippets int) ([]string, error) {
@@ -102,7 +131,7 @@ func (s *FileStore) SearchSemanticWithText(namespace string, query []float32, qu
 	}
 
 	dir := s.semanticDir(namespace)
-	path := filepath.Join(dir, "items.jsonl")
+	path := filepath.Join(dir, semanticItemsFile)
 	data, err := readTailBytes(path, 2*1024*1024)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -114,6 +143,8 @@ func (s *FileStore) SearchSemanticWithText(namespace string, query []float32, qu
 	if len(lines) == 0 {
 		return nil, nil
 	}
+	vectors := openSemanticVectorReader(dir)
+	defer vectors.Close()
 
 	qn := vectorNorm(query)
 	if qn <= 0 {
@@ -141,11 +172,11 @@ func (s *FileStore) SearchSemanticWithText(namespace string, query []float32, qu
 		if len(line) == 0 {
 			continue
 		}
-		var r SemanticRecord
+		var r semanticIndexRecord
 		if err := json.Unmarshal(line, &r); err != nil {
 			continue
 		}
-		if len(r.Vec) == 0 || r.Norm <= 0 {
+		if !r.hasVector() || r.Norm <= 0 {
 			continue
 		}
 		if maxAgeDays > 0 && !r.TS.IsZero() {
@@ -153,7 +184,11 @@ func (s *FileStore) SearchSemanticWithText(namespace string, query []float32, qu
 				continue
 			}
 		}
-		score := cosineSim(query, qn, r.Vec, r.Norm)
+		vec := vectors.vector(r)
+		if len(vec) == 0 {
+			continue
+		}
+		score := cosineSim(query, qn, vec, r.Norm)
 		if score <= 0 {
 			continue
 		}
@@ -221,7 +256,7 @@ func (s *FileStore) ReadSemanticTail(namespace string, limit int) ([]SemanticRec
 	}
 
 	dir := s.semanticDir(namespace)
-	path := filepath.Join(dir, "items.jsonl")
+	path := filepath.Join(dir, semanticItemsFile)
 	data, err := readTailBytes(path, 2*1024*1024)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -233,6 +268,8 @@ func (s *FileStore) ReadSemanticTail(namespace string, limit int) ([]SemanticRec
 	if len(lines) == 0 {
 		return nil, nil
 	}
+	vectors := openSemanticVectorReader(dir)
+	defer vectors.Close()
 
 	out := make([]SemanticRecord, 0, limit)
 	for i := len(lines) - 1; i >= 0; i-- {
@@ -243,14 +280,16 @@ func (s *FileStore) ReadSemanticTail(namespace string, limit int) ([]SemanticRec
 		if len(line) == 0 {
 			continue
 		}
-		var r SemanticRecord
+		var r semanticIndexRecord
 		if err := json.Unmarshal(line, &r); err != nil {
 			continue
 		}
 		if strings.TrimSpace(r.Text) == "" {
 			continue
 		}
-		out = append(out, r)
+		rec := r.record()
+		rec.Vec = vectors.vector(r)
+		out = append(out, rec)
 	}
 	// Reverse to chronological order
 	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
@@ -277,7 +316,7 @@ func (s *FileStore) ListSemanticNamespaces(limit int) ([]SemanticNamespaceInfo,
 			continue
 		}
 		dir := filepath.Join(root, e.Name())
-		itemsPath := filepath.Join(dir, "items.jsonl")
+		itemsPath := filepath.Join(dir, semanticItemsFile)
 		fi, err := os.Stat(itemsPath)
 		if err != nil {
 			continue
@@ -0,0 +1,398 @@
+package memory
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Semantic namespaces keep record metadata in items.jsonl (the index) and the
+// embedding vectors in vectors.bin. Index lines reference their vector by byte
+// offset, dimension and encoding. Older namespaces stored vectors inline as JSON
+// float arrays; those lines are still readable and are rewritten on first write.
+
+const (
+	semanticItemsFile   = "items.jsonl"
+	semanticVectorsFile = "vectors.bin"
+
+	vectorEncodingF16  = "f16"
+	vectorEncodingI8   = "i8"
+	vectorEncodingJSON = "json"
+)
+
+// semanticVectorsMagic prefixes vectors.bin so foreign files are never decoded.
+var semanticVectorsMagic = []byte("PPVEC1\n\x00")
+
+var (
+	// semanticWriteMu serializes vector appends; offsets come from the file size.
+	semanticWriteMu sync.Mutex
+	// semanticMigrated remembers namespace dirs already checked for inline vectors.
+	semanticMigrated sync.Map
+)
+
+// semanticIndexRecord is the on-disk shape of an items.jsonl line.
+type semanticIndexRecord struct {
+	TS      time.Time `json:"ts"`
+	Role    string    `json:"role,omitempty"`
+	Text    string    `json:"text"`
+	Vec     []float32 `json:"vec,omitempty"`
+	Norm    float32   `json:"norm"`
+	Source  string    `json:"source,omitempty"`
+	Session string    `json:"session,omitempty"`
+	Repo    string    `json:"repo,omitempty"`
+	Enc     string    `json:"enc,omitempty"`
+	Off     int64     `json:"off,omitempty"`
+	Dim     int       `json:"dim,omitempty"`
+	Scale   float32   `json:"scale,omitempty"`
+}
+
+func (r semanticIndexRecord) record() SemanticRecord {
+	return SemanticRecord{TS: r.TS, Role: r.Role, Text: r.Text, Vec: r.Vec, Norm: r.Norm, Source: r.Source, Session: r.Session, Repo: r.Repo}
+}
+
+func indexRecordFrom(r SemanticRecord) semanticIndexRecord {
+	return semanticIndexRecord{TS: r.TS, Role: r.Role, Text: r.Text, Vec: r.Vec, Norm: r.Norm, Source: r.Source, Session: r.Session, Repo: r.Repo}
+}
+
+// hasVector reports whether the index line carries a vector, inline or external.
+func (r semanticIndexRecord) hasVector() bool {
+	return len(r.Vec) > 0 || (r.Enc != "" && r.Dim > 0)
+}
+
+func semanticVectorEncoding() string {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("CLIPROXY_SEMANTIC_VECTOR_FORMAT"))) {
+	case "i8", "int8":
+		return vectorEncodingI8
+	case "json", "inline", "f32":
+		return vectorEncodingJSON
+	default:
+		return vectorEncodingF16
+	}
+}
+
+// encodeVector packs vec using enc and returns the payload and dequantization scale.
+func encodeVector(vec []float32, enc string) ([]byte, float32) {
+	switch enc {
+	case vectorEncodingI8:
+		var maxAbs float32
+		for _, v := range vec {
+			if a := float32(math.Abs(float64(v))); a > maxAbs {
+				maxAbs = a
+			}
+		}
+		scale := maxAbs / 127
+		out := make([]byte, len(vec))
+		if scale == 0 {
+			return out, 0
+		}
+		for i, v := range vec {
+			q := math.Round(float64(v / scale))
+			if q > 127 {
+				q = 127
+			} else if q < -127 {
+				q = -127
+			}
+			out[i] = byte(int8(q))
+		}
+		return out, scale
+	default:
+		out := make([]byte, 2*len(vec))
+		for i, v := range vec {
+			binary.LittleEndian.PutUint16(out[2*i:], float32ToHalf(v))
+		}
+		return out, 0
+	}
+}
+
+func decodeVector(data []byte, enc string, dim int, scale float32) ([]float32, error) {
+	switch enc {
+	case vectorEncodingI8:
+		if len(data) < dim {
+			return nil, io.ErrUnexpectedEOF
+		}
+		out := make([]float32, dim)
+		for i := 0; i < dim; i++ {
+			out[i] = float32(int8(data[i])) * scale
+		}
+		return out, nil
+	case vectorEncodingF16:
+		if len(data) < 2*dim {
+			return nil, io.ErrUnexpectedEOF
+		}
+		out := make([]float32, dim)
+		for i := 0; i < dim; i++ {
+			out[i] = halfToFloat32(binary.LittleEndian.Uint16(data[2*i:]))
+		}
+		return out, nil
+	default:
+		return nil, errors.New("unknown vector encoding " + enc)
+	}
+}
+
+func encodedVectorSize(enc string, dim int) int {
+	if enc == vectorEncodingI8 {
+		return dim
+	}
+	return 2 * dim
+}
+
+// float32ToHalf converts to IEEE 754 binary16 with round-to-nearest-even.
+func float32ToHalf(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int32(bits>>23) & 0xff
+	mant := bits & 0x7fffff
+
+	switch {
+	case exp == 0xff:
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	case exp-127 > 15:
+		return sign | 0x7c00
+	case exp-127 >= -14:
+		half := uint32(exp-127+15)<<10 | mant>>13
+		rem := mant & 0x1fff
+		if rem > 0x1000 || (rem == 0x1000 && half&1 == 1) {
+			half++
+		}
+		return sign | uint16(half)
+	case exp-127 >= -25:
+		mant |= 0x800000
+		shift := uint32(-(exp - 127) - 14 + 13)
+		half := mant >> shift
+		rem := mant & (1<<shift - 1)
+		halfway := uint32(1) << (shift - 1)
+		if rem > halfway || (rem == halfway && half&1 == 1) {
+			half++
+		}
+		return sign | uint16(half)
+	default:
+		return sign
+	}
+}
+
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+
+	switch {
+	case exp == 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	case exp == 0:
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		// Subnormal: normalize the mantissa.
+		e := uint32(127 - 15 + 1)
+		for mant&0x400 == 0 {
+			mant <<= 1
+			e--
+		}
+		mant &= 0x3ff
+		return math.Float32frombits(sign | e<<23 | mant<<13)
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+	}
+}
+
+// semanticVectorWriter appends encoded vectors to vectors.bin.
+type semanticVectorWriter struct {
+	f   *os.File
+	w   *bufio.Writer
+	off int64
+	enc string
+}
+
+func openSemanticVectorWriter(path string, enc string) (*semanticVectorWriter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	vw := &semanticVectorWriter{f: f, w: bufio.NewWriterSize(f, 64*1024), off: st.Size(), enc: enc}
+	if vw.off == 0 {
+		n, _ := vw.w.Write(semanticVectorsMagic)
+		vw.off += int64(n)
+	}
+	return vw, nil
+}
+
+// put stores rec.Vec externally and rewrites rec to reference it.
+func (vw *semanticVectorWriter) put(rec *semanticIndexRecord) error {
+	payload, scale := encodeVector(rec.Vec, vw.enc)
+	if _, err := vw.w.Write(payload); err != nil {
+		return err
+	}
+	rec.Enc = vw.enc
+	rec.Off = vw.off
+	rec.Dim = len(rec.Vec)
+	rec.Scale = scale
+	rec.Vec = nil
+	vw.off += int64(len(payload))
+	return nil
+}
+
+func (vw *semanticVectorWriter) Close() error {
+	errFlush := vw.w.Flush()
+	errClose := vw.f.Close()
+	if errFlush != nil {
+		return errFlush
+	}
+	return errClose
+}
+
+// semanticVectorReader resolves external vectors referenced by index lines.
+type semanticVectorReader struct {
+	f *os.File
+}
+
+func openSemanticVectorReader(dir string) *semanticVectorReader {
+	f, err := os.Open(filepath.Join(dir, semanticVectorsFile))
+	if err != nil {
+		return &semanticVectorReader{}
+	}
+	head := make([]byte, len(semanticVectorsMagic))
+	if _, err := io.ReadFull(f, head); err != nil || !bytes.Equal(head, semanticVectorsMagic) {
+		_ = f.Close()
+		return &semanticVectorReader{}
+	}
+	return &semanticVectorReader{f: f}
+}
+
+// vector returns the record's vector, decoding it from vectors.bin when stored externally.
+func (vr *semanticVectorReader) vector(rec semanticIndexRecord) []float32 {
+	if len(rec.Vec) > 0 {
+		return rec.Vec
+	}
+	if vr == nil || vr.f == nil || rec.Enc == "" || rec.Dim <= 0 || rec.Off < int64(len(semanticVectorsMagic)) {
+		return nil
+	}
+	buf := make([]byte, encodedVectorSize(rec.Enc, rec.Dim))
+	if _, err := vr.f.ReadAt(buf, rec.Off); err != nil {
+		return nil
+	}
+	vec, err := decodeVector(buf, rec.Enc, rec.Dim, rec.Scale)
+	if err != nil {
+		return nil
+	}
+	return vec
+}
+
+func (vr *semanticVectorReader) Close() {
+	if vr != nil && vr.f != nil {
+		_ = vr.f.Close()
+	}
+}
+
+// migrateSemanticDir moves inline vectors out of items.jsonl once per process.
+// Callers must hold semanticWriteMu.
+func migrateSemanticDir(dir string) {
+	if semanticVectorEncoding() == vectorEncodingJSON {
+		return
+	}
+	if _, done := semanticMigrated.LoadOrStore(dir, struct{}{}); done {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(dir, semanticItemsFile))
+	if err != nil || !bytes.Contains(data, []byte(`"vec":[`)) {
+		return
+	}
+	if err := rewriteSemanticDir(dir); err != nil {
+		semanticMigrated.Delete(dir)
+	}
+}
+
+// rewriteSemanticDir rebuilds items.jsonl and vectors.bin so every vector is stored
+// with the configured encoding and vectors.bin only holds vectors still indexed.
+// Callers must hold semanticWriteMu.
+func rewriteSemanticDir(dir string) error {
+	itemsPath := filepath.Join(dir, semanticItemsFile)
+	vectorsPath := filepath.Join(dir, semanticVectorsFile)
+	data, err := os.ReadFile(itemsPath)
+	if err != nil {
+		return err
+	}
+	enc := semanticVectorEncoding()
+
+	reader := openSemanticVectorReader(dir)
+	defer reader.Close()
+
+	tmpVectors := vectorsPath + ".tmp"
+	_ = os.Remove(tmpVectors)
+	var writer *semanticVectorWriter
+	if enc != vectorEncodingJSON {
+		writer, err = openSemanticVectorWriter(tmpVectors, enc)
+		if err != nil {
+			return err
+		}
+	}
+
+	var out bytes.Buffer
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var rec semanticIndexRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			continue
+		}
+		vec := reader.vector(rec)
+		if len(vec) == 0 {
+			continue
+		}
+		rec.Vec, rec.Enc, rec.Off, rec.Dim, rec.Scale = vec, "", 0, 0, 0
+		if writer != nil {
+			if err := writer.put(&rec); err != nil {
+				_ = writer.Close()
+				_ = os.Remove(tmpVectors)
+				return err
+			}
+		}
+		b, err := json.Marshal(rec)
+		if err != nil {
+			continue
+		}
+		out.Write(b)
+		out.WriteByte('\n')
+	}
+
+	if writer != nil {
+		if err := writer.Close(); err != nil {
+			_ = os.Remove(tmpVectors)
+			return err
+		}
+	}
+	tmpItems := itemsPath + ".tmp"
+	if err := os.WriteFile(tmpItems, out.Bytes(), 0o644); err != nil {
+		_ = os.Remove(tmpVectors)
+		return err
+	}
+	reader.Close()
+	if writer != nil {
+		if err := os.Rename(tmpVectors, vectorsPath); err != nil {
+			_ = os.Remove(tmpItems)
+			_ = os.Remove(tmpVectors)
+			return err
+		}
+	} else {
+		_ = os.Remove(vectorsPath)
+	}
+	return os.Rename(tmpItems, itemsPath)
+}
@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHalfFloatRoundTrip(t *testing.T) {
+	cases := []float32{0, 1, -1, 0.5, 0.1, -0.333, 65504, 6.1e-5, 1e-7}
+	for _, v := range cases {
+		got := halfToFloat32(float32ToHalf(v))
+		if diff := math.Abs(float64(got - v)); diff > math.Abs(float64(v))*1e-3+6e-8 {
+			t.Errorf("half round trip %v -> %v", v, got)
+		}
+	}
+	if got := halfToFloat32(float32ToHalf(1e6)); !math.IsInf(float64(got), 1) {
+		t.Errorf("overflow should become +Inf, got %v", got)
+	}
+}
+
+func TestEncodeVectorInt8(t *testing.T) {
+	vec := []float32{0.5, -1, 0.25, 0}
+	payload, scale := encodeVector(vec, vectorEncodingI8)
+	if len(payload) != len(vec) {
+		t.Fatalf("payload size = %d, want %d", len(payload), len(vec))
+	}
+	got, err := decodeVector(payload, vectorEncodingI8, len(vec), scale)
+	if err != nil {
+		t.Fatalf("decodeVector() error = %v", err)
+	}
+	for i := range vec {
+		if math.Abs(float64(got[i]-vec[i])) > 0.01 {
+			t.Errorf("vec[%d] = %v, want ~%v", i, got[i], vec[i])
+		}
+	}
+}
+
+func TestAppendSemantic_StoresVectorsOutOfLine(t *testing.T) {
+	for _, enc := range []string{"f16", "i8"} {
+		t.Run(enc, func(t *testing.T) {
+			t.Setenv("CLIPROXY_SEMANTIC_VECTOR_FORMAT", enc)
+			store := NewFileStore(t.TempDir())
+			ns := "compact-" + enc
+			if err := store.AppendSemantic(ns, []SemanticRecord{
+				{Text: "alpha topic", Vec: []float32{1, 0, 0}},
+				{Text: "beta topic", Vec: []float32{0, 1, 0}},
+			}); err != nil {
+				t.Fatalf("AppendSemantic() error = %v", err)
+			}
+
+			dir := store.semanticDir(ns)
+			items, err := os.ReadFile(filepath.Join(dir, semanticItemsFile))
+			if err != nil {
+				t.Fatalf("read index: %v", err)
+			}
+			if strings.Contains(string(items), `"vec":`) {
+				t.Fatalf("index should not contain inline vectors: %s", items)
+			}
+			if _, err := os.Stat(filepath.Join(dir, semanticVectorsFile)); err != nil {
+				t.Fatalf("vectors.bin missing: %v", err)
+			}
+
+			snips, err := store.SearchSemantic(ns, []float32{0, 1, 0}, 0, 1)
+			if err != nil {
+				t.Fatalf("SearchSemantic() error = %v", err)
+			}
+			if len(snips) != 1 || snips[0] != "beta topic" {
+				t.Fatalf("SearchSemantic() = %v, want [beta topic]", snips)
+			}
+			tail, err := store.ReadSemanticTail(ns, 10)
+			if err != nil || len(tail) != 2 || len(tail[0].Vec) != 3 {
+				t.Fatalf("ReadSemanticTail() = %+v, %v", tail, err)
+			}
+		})
+	}
+}
+
+func TestAppendSemantic_MigratesInlineVectors(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	ns := "legacy-ns"
+	dir := store.semanticDir(ns)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	legacy, _ := json.Marshal(SemanticRecord{TS: time.Now(), Text: "legacy entry", Vec: []float32{0, 0, 1}, Norm: 1})
+	if err := os.WriteFile(filepath.Join(dir, semanticItemsFile), append(legacy, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Legacy lines are readable before migration.
+	snips, err := store.SearchSemantic(ns, []float32{0, 0, 1}, 0, 1)
+	if err != nil || len(snips) != 1 || snips[0] != "legacy entry" {
+		t.Fatalf("search before migration = %v, %v", snips, err)
+	}
+
+	if err := store.AppendSemantic(ns, []SemanticRecord{{Text: "new entry", Vec: []float32{1, 0, 0}}}); err != nil {
+		t.Fatalf("AppendSemantic() error = %v", err)
+	}
+	items, err := os.ReadFile(filepath.Join(dir, semanticItemsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(items), `"vec":`) {
+		t.Fatalf("inline vectors should be migrated: %s", items)
+	}
+	snips, err = store.SearchSemantic(ns, []float32{0, 0, 1}, 0, 1)
+	if err != nil || len(snips) != 1 || snips[0] != "legacy entry" {
+		t.Fatalf("search after migration = %v, %v", snips, err)
+	}
+}
+
+func TestPruneSemantic_CompactsVectors(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	ns := "compact-prune"
+	records := make([]SemanticRecord, 200)
+	for i := range records {
+		vec := make([]float32, 64)
+		vec[i%64] = 1
+		records[i] = SemanticRecord{Text: strings.Repeat("x", 40) + string(rune('A'+i%26)) + time.Duration(i).String(), Vec: vec}
+	}
+	if err := store.AppendSemantic(ns, records); err != nil {
+		t.Fatal(err)
+	}
+	vectorsPath := filepath.Join(store.semanticDir(ns), semanticVectorsFile)
+	before, _ := os.Stat(vectorsPath)
+
+	res, err := store.PruneSemantic(0, 0, 2000)
+	if err != nil {
+		t.Fatalf("PruneSemantic() error = %v", err)
+	}
+	after, _ := os.Stat(vectorsPath)
+	if res.SemanticNamespacesTrimmed != 1 || after.Size() >= before.Size() {
+		t.Fatalf("expected vectors.bin to shrink: trimmed=%d before=%d after=%d", res.SemanticNamespacesTrimmed, before.Size(), after.Size())
+	}
+	tail, err := store.ReadSemanticTail(ns, 200)
+	if err != nil || len(tail) == 0 {
+		t.Fatalf("ReadSemanticTail() after compaction = %d, %v", len(tail), err)
+	}
+	for _, r := range tail {
+		if len(r.Vec) != 64 {
+			t.Fatalf("vector lost after compaction for %q", r.Text)
+		}
+	}
+}
+
+func TestAppend_ArchivesOldEvents(t *testing.T) {
+	t.Setenv("CLIPROXY_MEMORY_EVENTS_ROTATE_BYTES", "4096")
+	store := NewFileStore(t.TempDir())
+	session := "archive-session"
+	for i := 0; i < 50; i++ {
+		if err := store.Append(session, []Event{{Kind: "message", Role: "user", Text: strings.Repeat("event ", 30) + time.Duration(i).String()}}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	dir := store.sessionDir(session)
+	fi, err := os.Stat(filepath.Join(dir, sessionEventsFile))
+	if err != nil || fi.Size() > 4096 {
+		t.Fatalf("events.jsonl should stay under the rotation threshold: %v, %v", fi, err)
+	}
+	f, err := os.Open(filepath.Join(dir, sessionEventsArchiveFile))
+	if err != nil {
+		t.Fatalf("archive missing: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := gzip.NewReader(f); err != nil {
+		t.Fatalf("archive is not gzip: %v", err)
+	}
+
+	archived, err := store.ReadArchivedEvents(session, 1000)
+	if err != nil {
+		t.Fatalf("ReadArchivedEvents() error = %v", err)
+	}
+	live, err := store.ReadEventTail(session, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archived)+len(live) != 50 {
+		t.Fatalf("archived %d + live %d events, want 50", len(archived), len(live))
+	}
+	info, err := store.GetSessionInfo(session)
+	if err != nil || info.ArchivedBytes == 0 {
+		t.Fatalf("session info should report archived bytes: %+v, %v", info, err)
+	}
+}
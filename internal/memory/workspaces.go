@@ -0,0 +1,247 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/filelock"
+)
+
+// Registered workspaces pin a directory tree to a semantic memory namespace, so requests
+// from anywhere inside the tree share retrieval context regardless of which repo hint the
+// client sends. The registry is a small JSON file next to the session and semantic stores.
+
+const workspacesFile = "workspaces.json"
+
+// Workspace maps a project directory to the semantic namespace used for its requests.
+type Workspace struct {
+	Path        string    `json:"path"`
+	Namespace   string    `json:"namespace"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type workspaceCacheEntry struct {
+	modTime time.Time
+	size    int64
+	items   []Workspace
+}
+
+var (
+	workspaceCacheMu sync.Mutex
+	workspaceCache   = map[string]workspaceCacheEntry{}
+)
+
+// NormalizeWorkspacePath cleans p into the slash-separated form used for matching.
+func NormalizeWorkspacePath(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return ""
+	}
+	p = strings.ReplaceAll(filepath.ToSlash(p), "\\", "/")
+	p = path.Clean(p)
+	if len(p) > 1 {
+		p = strings.TrimSuffix(p, "/")
+	}
+	return p
+}
+
+// ListWorkspaces returns the registered workspaces sorted by path.
+func (s *FileStore) ListWorkspaces() ([]Workspace, error) {
+	if s == nil || s.BaseDir == "" {
+		return nil, errors.New("memory store not configured")
+	}
+	items, err := s.loadWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+	return append([]Workspace(nil), items...), nil
+}
+
+// RegisterWorkspace adds or updates the workspace for ws.Path. An empty namespace
+// defaults to the directory's base name.
+func (s *FileStore) RegisterWorkspace(ws Workspace) (Workspace, error) {
+	if s == nil || s.BaseDir == "" {
+		return Workspace{}, errors.New("memory store not configured")
+	}
+	ws.Path = NormalizeWorkspacePath(ws.Path)
+	if ws.Path == "" || ws.Path == "." {
+		return Workspace{}, errors.New("workspace path is required")
+	}
+	ws.Namespace = strings.TrimSpace(ws.Namespace)
+	if ws.Namespace == "" {
+		ws.Namespace = path.Base(ws.Path)
+	}
+	ws.Description = strings.TrimSpace(ws.Description)
+
+	var saved Workspace
+	err := s.updateWorkspaces(func(items []Workspace) []Workspace {
+		now := time.Now().UTC()
+		for i := range items {
+			if samePath(items[i].Path, ws.Path) {
+				ws.CreatedAt = items[i].CreatedAt
+				ws.UpdatedAt = now
+				items[i] = ws
+				saved = ws
+				return items
+			}
+		}
+		ws.CreatedAt = now
+		ws.UpdatedAt = now
+		saved = ws
+		return append(items, ws)
+	})
+	return saved, err
+}
+
+// UnregisterWorkspace removes the workspaces whose path or namespace equals key.
+// It reports whether anything was removed.
+func (s *FileStore) UnregisterWorkspace(key string) (bool, error) {
+	if s == nil || s.BaseDir == "" {
+		return false, errors.New("memory store not configured")
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return false, nil
+	}
+	normalized := NormalizeWorkspacePath(key)
+	removed := false
+	err := s.updateWorkspaces(func(items []Workspace) []Workspace {
+		out := items[:0]
+		for _, ws := range items {
+			if ws.Namespace == key || samePath(ws.Path, normalized) {
+				removed = true
+				continue
+			}
+			out = append(out, ws)
+		}
+		return out
+	})
+	return removed, err
+}
+
+// ResolveWorkspace maps a namespace or path hint to a registered workspace. A hint
+// matches when it equals a workspace namespace or lies inside a workspace path; the
+// deepest matching path wins.
+func (s *FileStore) ResolveWorkspace(hint string) (Workspace, bool) {
+	hint = strings.TrimSpace(hint)
+	if s == nil || s.BaseDir == "" || hint == "" {
+		return Workspace{}, false
+	}
+	items, err := s.loadWorkspaces()
+	if err != nil || len(items) == 0 {
+		return Workspace{}, false
+	}
+	for _, ws := range items {
+		if ws.Namespace == hint {
+			return ws, true
+		}
+	}
+	p := NormalizeWorkspacePath(hint)
+	best := -1
+	for i, ws := range items {
+		if !pathWithin(p, ws.Path) {
+			continue
+		}
+		if best < 0 || len(ws.Path) > len(items[best].Path) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return Workspace{}, false
+	}
+	return items[best], true
+}
+
+func (s *FileStore) workspacesPath() string {
+	return filepath.Join(s.BaseDir, workspacesFile)
+}
+
+// loadWorkspaces reads the registry, reusing the parsed copy while the file is unchanged.
+func (s *FileStore) loadWorkspaces() ([]Workspace, error) {
+	p := s.workspacesPath()
+	fi, err := os.Stat(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	workspaceCacheMu.Lock()
+	entry, ok := workspaceCache[p]
+	workspaceCacheMu.Unlock()
+	if ok && entry.modTime.Equal(fi.ModTime()) && entry.size == fi.Size() {
+		return entry.items, nil
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	var items []Workspace
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+	}
+	workspaceCacheMu.Lock()
+	workspaceCache[p] = workspaceCacheEntry{modTime: fi.ModTime(), size: fi.Size(), items: items}
+	workspaceCacheMu.Unlock()
+	return items, nil
+}
+
+func (s *FileStore) updateWorkspaces(fn func([]Workspace) []Workspace) error {
+	if err := os.MkdirAll(s.BaseDir, 0o755); err != nil {
+		return err
+	}
+	p := s.workspacesPath()
+	return filelock.WithLock(p, func() error {
+		current, err := s.loadWorkspaces()
+		if err != nil {
+			return err
+		}
+		items := fn(append([]Workspace(nil), current...))
+		sort.Slice(items, func(i, j int) bool { return items[i].Path < items[j].Path })
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return err
+		}
+		tmp := p + ".tmp"
+		if err := os.WriteFile(tmp, data, 0o644); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, p); err != nil {
+			return err
+		}
+		workspaceCacheMu.Lock()
+		delete(workspaceCache, p)
+		workspaceCacheMu.Unlock()
+		return nil
+	})
+}
+
+func samePath(a, b string) bool {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// pathWithin reports whether p equals root or is nested below it.
+func pathWithin(p, root string) bool {
+	if root == "" || len(p) < len(root) {
+		return false
+	}
+	if !samePath(p[:len(root)], root) {
+		return false
+	}
+	return len(p) == len(root) || root == "/" || p[len(root)] == '/'
+}
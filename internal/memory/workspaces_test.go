@@ -0,0 +1,62 @@
+package memory
+
+import "testing"
+
+func TestRegisterWorkspace_ResolvesNestedPaths(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	if _, err := store.RegisterWorkspace(Workspace{Path: "/src/app/", Namespace: "app", Description: "main app"}); err != nil {
+		t.Fatalf("RegisterWorkspace() error = %v", err)
+	}
+	if _, err := store.RegisterWorkspace(Workspace{Path: "/src/app/plugins/billing"}); err != nil {
+		t.Fatalf("RegisterWorkspace() error = %v", err)
+	}
+
+	cases := []struct {
+		hint string
+		want string
+		ok   bool
+	}{
+		{hint: "/src/app", want: "app", ok: true},
+		{hint: "/src/app/internal/x.go", want: "app", ok: true},
+		{hint: "/src/app/plugins/billing/api", want: "billing", ok: true},
+		{hint: "app", want: "app", ok: true},
+		{hint: "/src/application", ok: false},
+		{hint: "/other", ok: false},
+	}
+	for _, tc := range cases {
+		ws, ok := store.ResolveWorkspace(tc.hint)
+		if ok != tc.ok || ws.Namespace != tc.want {
+			t.Errorf("ResolveWorkspace(%q) = %q, %v; want %q, %v", tc.hint, ws.Namespace, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestRegisterWorkspace_UpdatesAndRemoves(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	first, err := store.RegisterWorkspace(Workspace{Path: "/src/app", Namespace: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err := store.RegisterWorkspace(Workspace{Path: "/src/app/", Namespace: "app-v2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.CreatedAt.Equal(first.CreatedAt) {
+		t.Fatalf("update should keep CreatedAt: %v vs %v", updated.CreatedAt, first.CreatedAt)
+	}
+	items, err := store.ListWorkspaces()
+	if err != nil || len(items) != 1 || items[0].Namespace != "app-v2" {
+		t.Fatalf("ListWorkspaces() = %+v, %v", items, err)
+	}
+
+	removed, err := store.UnregisterWorkspace("app-v2")
+	if err != nil || !removed {
+		t.Fatalf("UnregisterWorkspace() = %v, %v", removed, err)
+	}
+	if _, ok := store.ResolveWorkspace("/src/app"); ok {
+		t.Fatal("workspace should no longer resolve after removal")
+	}
+	if _, err := store.RegisterWorkspace(Workspace{Path: " "}); err == nil {
+		t.Fatal("expected error for empty path")
+	}
+}
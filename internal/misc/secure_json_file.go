@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/filelock"
 )
 
 // WriteJSONFileSecure writes JSON to path atomically with restrictive permissions.
@@ -14,6 +16,12 @@ func WriteJSONFileSecure(path string, value any, indent bool) error {
 		return fmt.Errorf("create directory failed: %w", err)
 	}
 
+	unlock, err := filelock.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".auth-*.tmp")
 	if err != nil {
 		return fmt.Errorf("create temp file failed: %w", err)
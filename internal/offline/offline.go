@@ -0,0 +1,262 @@
+// Package offline tracks whether the engine should refuse upstream provider calls.
+//
+// Offline mode can be forced from config or detected automatically by probing a
+// well-known endpoint. While offline, only local providers (loopback base URLs or
+// providers explicitly listed as local) are allowed to execute; everything else
+// is rejected with a distinct error so clients can tell it apart from outages.
+package offline
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultProbeAddr is dialed to detect connectivity when no probe target is configured.
+	DefaultProbeAddr = "www.google.com:443"
+	// DefaultProbeInterval is used when auto-detect is enabled without an explicit interval.
+	DefaultProbeInterval = 30 * time.Second
+
+	probeDialTimeout = 5 * time.Second
+)
+
+// Settings controls offline mode behavior.
+type Settings struct {
+	// Forced puts the engine into offline mode regardless of connectivity.
+	Forced bool
+	// AutoDetect enables the background connectivity probe.
+	AutoDetect bool
+	// ProbeAddr is a host:port or URL dialed by the connectivity probe.
+	ProbeAddr string
+	// ProbeInterval controls how often the probe runs.
+	ProbeInterval time.Duration
+	// LocalProviders lists provider keys that remain usable while offline.
+	LocalProviders []string
+}
+
+// Status is a point-in-time snapshot of offline mode state.
+type Status struct {
+	Offline        bool      `json:"offline"`
+	Forced         bool      `json:"forced"`
+	AutoDetect     bool      `json:"auto_detect"`
+	Detected       bool      `json:"detected_offline"`
+	ProbeAddr      string    `json:"probe_addr,omitempty"`
+	LastProbe      time.Time `json:"last_probe,omitempty"`
+	LastChange     time.Time `json:"last_change,omitempty"`
+	LocalProviders []string  `json:"local_providers,omitempty"`
+}
+
+var (
+	forced   atomic.Bool
+	detected atomic.Bool
+
+	mu             sync.RWMutex
+	current        Settings
+	localProviders map[string]struct{}
+	lastProbe      time.Time
+	lastChange     time.Time
+	monitorCancel  context.CancelFunc
+
+	// probeFunc is replaceable in tests.
+	probeFunc = dialProbe
+)
+
+// Apply installs new settings and (re)starts or stops the connectivity monitor.
+func Apply(s Settings) {
+	s.ProbeAddr = strings.TrimSpace(s.ProbeAddr)
+	if s.ProbeAddr == "" {
+		s.ProbeAddr = DefaultProbeAddr
+	}
+	if s.ProbeInterval <= 0 {
+		s.ProbeInterval = DefaultProbeInterval
+	}
+	locals := make(map[string]struct{}, len(s.LocalProviders))
+	for _, p := range s.LocalProviders {
+		if key := strings.ToLower(strings.TrimSpace(p)); key != "" {
+			locals[key] = struct{}{}
+		}
+	}
+
+	mu.Lock()
+	previous := current
+	current = s
+	localProviders = locals
+	restart := previous.AutoDetect != s.AutoDetect || previous.ProbeAddr != s.ProbeAddr || previous.ProbeInterval != s.ProbeInterval
+	if restart && monitorCancel != nil {
+		monitorCancel()
+		monitorCancel = nil
+	}
+	var ctx context.Context
+	if s.AutoDetect && monitorCancel == nil {
+		ctx, monitorCancel = context.WithCancel(context.Background())
+	}
+	if previous.Forced != s.Forced {
+		lastChange = time.Now()
+	}
+	mu.Unlock()
+
+	forced.Store(s.Forced)
+	if !s.AutoDetect {
+		detected.Store(false)
+	}
+	if previous.Forced != s.Forced {
+		log.Infof("offline mode %s by configuration", onOff(s.Forced))
+	}
+	if ctx != nil {
+		go runMonitor(ctx, s.ProbeAddr, s.ProbeInterval)
+	}
+}
+
+// Stop halts the connectivity monitor.
+func Stop() {
+	mu.Lock()
+	if monitorCancel != nil {
+		monitorCancel()
+		monitorCancel = nil
+	}
+	mu.Unlock()
+}
+
+// Active reports whether upstream calls should currently be refused.
+func Active() bool {
+	return forced.Load() || detected.Load()
+}
+
+// SetForced toggles forced offline mode at runtime without touching the probe settings.
+func SetForced(enabled bool) {
+	if forced.Swap(enabled) == enabled {
+		return
+	}
+	mu.Lock()
+	current.Forced = enabled
+	lastChange = time.Now()
+	mu.Unlock()
+	log.Infof("offline mode %s", onOff(enabled))
+}
+
+// IsLocal reports whether a provider endpoint can be used while offline.
+// Providers listed in Settings.LocalProviders and loopback base URLs are local.
+func IsLocal(provider, baseURL string) bool {
+	mu.RLock()
+	_, listed := localProviders[strings.ToLower(strings.TrimSpace(provider))]
+	mu.RUnlock()
+	if listed {
+		return true
+	}
+	return isLoopbackURL(baseURL)
+}
+
+// CurrentStatus returns a snapshot of offline mode state.
+func CurrentStatus() Status {
+	mu.RLock()
+	defer mu.RUnlock()
+	locals := make([]string, 0, len(current.LocalProviders))
+	locals = append(locals, current.LocalProviders...)
+	return Status{
+		Offline:        Active(),
+		Forced:         forced.Load(),
+		AutoDetect:     current.AutoDetect,
+		Detected:       detected.Load(),
+		ProbeAddr:      current.ProbeAddr,
+		LastProbe:      lastProbe,
+		LastChange:     lastChange,
+		LocalProviders: locals,
+	}
+}
+
+func runMonitor(ctx context.Context, addr string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		probeOnce(ctx, addr)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func probeOnce(ctx context.Context, addr string) {
+	reachable := probeFunc(ctx, addr)
+	if ctx.Err() != nil {
+		return
+	}
+	wasOffline := detected.Swap(!reachable)
+	mu.Lock()
+	lastProbe = time.Now()
+	if wasOffline == reachable {
+		lastChange = lastProbe
+	}
+	mu.Unlock()
+	switch {
+	case !wasOffline && !reachable:
+		log.Warnf("connectivity probe to %s failed; entering offline mode", addr)
+	case wasOffline && reachable:
+		log.Infof("connectivity restored (%s reachable); leaving offline mode", addr)
+	}
+}
+
+func dialProbe(ctx context.Context, addr string) bool {
+	target := probeTarget(addr)
+	dialCtx, cancel := context.WithTimeout(ctx, probeDialTimeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(dialCtx, "tcp", target)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// probeTarget converts a URL or bare host into a host:port suitable for dialing.
+func probeTarget(addr string) string {
+	if strings.Contains(addr, "://") {
+		if u, err := url.Parse(addr); err == nil && u.Host != "" {
+			if u.Port() != "" {
+				return u.Host
+			}
+			port := "443"
+			if u.Scheme == "http" {
+				port = "80"
+			}
+			return net.JoinHostPort(u.Hostname(), port)
+		}
+	}
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr
+	}
+	return net.JoinHostPort(addr, "443")
+}
+
+func isLoopbackURL(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func onOff(v bool) string {
+	if v {
+		return "enabled"
+	}
+	return "disabled"
+}
@@ -0,0 +1,79 @@
+package offline
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIsLocal(t *testing.T) {
+	Apply(Settings{LocalProviders: []string{"Ollama"}})
+	defer Apply(Settings{})
+
+	cases := []struct {
+		provider string
+		baseURL  string
+		want     bool
+	}{
+		{provider: "ollama", baseURL: "http://192.168.1.10:11434/v1", want: true},
+		{provider: "lmstudio", baseURL: "http://127.0.0.1:1234/v1", want: true},
+		{provider: "lmstudio", baseURL: "http://localhost:1234/v1", want: true},
+		{provider: "lmstudio", baseURL: "http://[::1]:1234/v1", want: true},
+		{provider: "claude", baseURL: "https://api.anthropic.com", want: false},
+		{provider: "gemini", baseURL: "", want: false},
+	}
+	for _, tc := range cases {
+		if got := IsLocal(tc.provider, tc.baseURL); got != tc.want {
+			t.Errorf("IsLocal(%q, %q) = %v, want %v", tc.provider, tc.baseURL, got, tc.want)
+		}
+	}
+}
+
+func TestForcedModeToggles(t *testing.T) {
+	Apply(Settings{Forced: true})
+	if !Active() {
+		t.Fatal("expected offline mode to be active when forced")
+	}
+	SetForced(false)
+	if Active() {
+		t.Fatal("expected offline mode to be inactive after SetForced(false)")
+	}
+	if CurrentStatus().Forced {
+		t.Fatal("expected status to report forced=false")
+	}
+	Apply(Settings{})
+}
+
+func TestProbeOnceRecoversAutomatically(t *testing.T) {
+	defer func() {
+		probeFunc = dialProbe
+		detected.Store(false)
+	}()
+
+	reachable := false
+	probeFunc = func(context.Context, string) bool { return reachable }
+
+	probeOnce(context.Background(), "example.invalid:443")
+	if !Active() {
+		t.Fatal("expected failed probe to enter offline mode")
+	}
+
+	reachable = true
+	probeOnce(context.Background(), "example.invalid:443")
+	if Active() {
+		t.Fatal("expected successful probe to leave offline mode")
+	}
+}
+
+func TestProbeTarget(t *testing.T) {
+	cases := map[string]string{
+		"www.google.com:443":         "www.google.com:443",
+		"example.com":                "example.com:443",
+		"http://example.com/health":  "example.com:80",
+		"https://example.com:8443/x": "example.com:8443",
+	}
+	for in, want := range cases {
+		if got := probeTarget(in); got != want {
+			t.Errorf("probeTarget(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
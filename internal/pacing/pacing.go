@@ -0,0 +1,188 @@
+// Package pacing tracks per-client request and token budgets so responses can advertise
+// how much headroom a client has left.
+//
+// Budgets are counted per authenticated client key in fixed one-minute windows. The API
+// middleware reserves one request when a generation call arrives and reports the
+// remaining budget in OpenAI-compatible x-ratelimit-* headers; token usage is added once
+// the upstream call reports it. Well-behaved clients can use the headers to slow down
+// before they are refused.
+package pacing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	coreusage "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/usage"
+)
+
+// Window is the length of one budget window.
+const Window = time.Minute
+
+// Settings controls client pacing.
+type Settings struct {
+	Enabled           bool
+	RequestsPerMinute int
+	TokensPerMinute   int
+	// Enforce refuses requests once a budget is exhausted instead of only advertising it.
+	Enforce bool
+}
+
+// Snapshot describes a client's budget after a reservation.
+type Snapshot struct {
+	LimitRequests     int
+	RemainingRequests int
+	LimitTokens       int
+	RemainingTokens   int
+	// Reset is how long until the current window ends and both budgets refill.
+	Reset time.Duration
+}
+
+// Exhausted reports whether either configured budget has run out.
+func (s Snapshot) Exhausted() bool {
+	return (s.LimitRequests > 0 && s.RemainingRequests <= 0) || (s.LimitTokens > 0 && s.RemainingTokens <= 0)
+}
+
+type clientWindow struct {
+	start    time.Time
+	requests int
+	tokens   int64
+}
+
+var (
+	mu       sync.Mutex
+	settings Settings
+	clients  = map[string]*clientWindow{}
+
+	// nowFunc is replaced in tests.
+	nowFunc = time.Now
+)
+
+func init() {
+	coreusage.RegisterPlugin(usagePlugin{})
+}
+
+// Apply replaces the settings. Existing windows are kept so reloads do not reset budgets.
+func Apply(s Settings) {
+	if s.RequestsPerMinute < 0 {
+		s.RequestsPerMinute = 0
+	}
+	if s.TokensPerMinute < 0 {
+		s.TokensPerMinute = 0
+	}
+	mu.Lock()
+	settings = s
+	if !enabledLocked() {
+		clients = map[string]*clientWindow{}
+	}
+	mu.Unlock()
+}
+
+// Enabled reports whether pacing is on and at least one budget is configured.
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return enabledLocked()
+}
+
+func enabledLocked() bool {
+	return settings.Enabled && (settings.RequestsPerMinute > 0 || settings.TokensPerMinute > 0)
+}
+
+// Reserve counts one request for client and returns the remaining budget. When Enforce is
+// set and the budget is already exhausted, the request is not counted and allowed is false.
+// While pacing is disabled it returns a zero Snapshot and true.
+func Reserve(client string) (snap Snapshot, allowed bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabledLocked() {
+		return Snapshot{}, true
+	}
+	now := nowFunc()
+	w := windowLocked(client, now)
+	if snap = snapshotLocked(w, now); settings.Enforce && snap.Exhausted() {
+		return snap, false
+	}
+	w.requests++
+	return snapshotLocked(w, now), true
+}
+
+// RecordTokens adds tokens consumed by client to its current window.
+func RecordTokens(client string, tokens int64) {
+	if tokens <= 0 {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabledLocked() || settings.TokensPerMinute <= 0 {
+		return
+	}
+	windowLocked(client, nowFunc()).tokens += tokens
+}
+
+func windowLocked(client string, now time.Time) *clientWindow {
+	w := clients[client]
+	if w == nil || now.Sub(w.start) >= Window {
+		if w == nil {
+			pruneLocked(now)
+		}
+		w = &clientWindow{start: now}
+		clients[client] = w
+	}
+	return w
+}
+
+// pruneLocked drops windows that ended long enough ago that they would be reset anyway.
+func pruneLocked(now time.Time) {
+	for key, w := range clients {
+		if now.Sub(w.start) >= 2*Window {
+			delete(clients, key)
+		}
+	}
+}
+
+func snapshotLocked(w *clientWindow, now time.Time) Snapshot {
+	snap := Snapshot{
+		LimitRequests: settings.RequestsPerMinute,
+		LimitTokens:   settings.TokensPerMinute,
+		Reset:         w.start.Add(Window).Sub(now),
+	}
+	if snap.LimitRequests > 0 {
+		snap.RemainingRequests = max(snap.LimitRequests-w.requests, 0)
+	}
+	if snap.LimitTokens > 0 {
+		snap.RemainingTokens = max(snap.LimitTokens-int(w.tokens), 0)
+	}
+	if snap.Reset < 0 {
+		snap.Reset = 0
+	}
+	return snap
+}
+
+// usagePlugin feeds token usage reported by executors into the client windows.
+type usagePlugin struct{}
+
+func (usagePlugin) HandleUsage(ctx context.Context, record coreusage.Record) {
+	if ctx == nil {
+		return
+	}
+	ginCtx, ok := ctx.Value("gin").(*gin.Context)
+	if !ok || ginCtx == nil {
+		return
+	}
+	tokens := record.Detail.TotalTokens
+	if tokens == 0 {
+		tokens = record.Detail.InputTokens + record.Detail.OutputTokens + record.Detail.ReasoningTokens
+	}
+	RecordTokens(ClientKey(ginCtx), tokens)
+}
+
+// ClientKey returns the identity budgets are tracked under: the authenticated client key,
+// or the remote IP when the request was not authenticated.
+func ClientKey(c *gin.Context) string {
+	if key := c.GetString("apiKey"); key != "" {
+		return "key:" + key
+	}
+	return "ip:" + c.ClientIP()
+}
@@ -0,0 +1,56 @@
+package pacing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReserveCountsRequestsAndTokensPerWindow(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(func() {
+		nowFunc = time.Now
+		Apply(Settings{})
+	})
+	Apply(Settings{Enabled: true, RequestsPerMinute: 2, TokensPerMinute: 100, Enforce: true})
+
+	snap, allowed := Reserve("key:a")
+	if !allowed || snap.RemainingRequests != 1 || snap.RemainingTokens != 100 || snap.Reset != Window {
+		t.Fatalf("first reserve = %+v, %v", snap, allowed)
+	}
+	RecordTokens("key:a", 40)
+	now = now.Add(20 * time.Second)
+	snap, allowed = Reserve("key:a")
+	if !allowed || snap.RemainingRequests != 0 || snap.RemainingTokens != 60 || snap.Reset != 40*time.Second {
+		t.Fatalf("second reserve = %+v, %v", snap, allowed)
+	}
+	if _, allowed = Reserve("key:a"); allowed {
+		t.Fatal("third reserve allowed with exhausted request budget")
+	}
+	if _, allowed = Reserve("key:b"); !allowed {
+		t.Fatal("other client affected by key:a budget")
+	}
+
+	now = now.Add(Window)
+	if snap, allowed = Reserve("key:a"); !allowed || snap.RemainingRequests != 1 || snap.RemainingTokens != 100 {
+		t.Fatalf("reserve after window reset = %+v, %v", snap, allowed)
+	}
+}
+
+func TestReserveAdvisoryModeNeverRefuses(t *testing.T) {
+	t.Cleanup(func() { Apply(Settings{}) })
+	Apply(Settings{Enabled: true, TokensPerMinute: 10})
+	RecordTokens("key:c", 50)
+	snap, allowed := Reserve("key:c")
+	if !allowed || snap.RemainingTokens != 0 || !snap.Exhausted() {
+		t.Fatalf("advisory reserve = %+v, %v", snap, allowed)
+	}
+
+	Apply(Settings{})
+	if Enabled() {
+		t.Fatal("pacing enabled without settings")
+	}
+	if snap, allowed = Reserve("key:c"); !allowed || snap != (Snapshot{}) {
+		t.Fatalf("disabled reserve = %+v, %v", snap, allowed)
+	}
+}
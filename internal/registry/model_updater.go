@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/offline"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -113,6 +114,10 @@ func tryStartupRefresh(ctx context.Context) {
 }
 
 func tryRefreshModels(ctx context.Context, label string) {
+	if offline.Active() {
+		log.Debugf("%s: offline mode active, serving cached model catalog", label)
+		return
+	}
 	oldData := getModels()
 
 	parsed, url := fetchModelsFromRemote(ctx)
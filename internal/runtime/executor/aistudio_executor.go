@@ -343,8 +343,17 @@ func (e *AIStudioExecutor) ExecuteStream(ctx context.Context, auth *cliproxyauth
 	return &cliproxyexecutor.StreamResult{Headers: firstEvent.Headers.Clone(), Chunks: out}, nil
 }
 
-// CountTokens counts tokens for the given request using the AI Studio API.
+// CountTokens counts tokens for the given request, preferring the AI Studio counting endpoint.
+// Identical requests are served from cache and a local estimate is used when the
+// endpoint is unavailable.
 func (e *AIStudioExecutor) CountTokens(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
+	return helps.CountTokensPreferNative(ctx, e.Identifier(), req, opts, func() (cliproxyexecutor.Response, error) {
+		return e.countTokensNative(ctx, auth, req, opts)
+	})
+}
+
+// countTokensNative counts tokens using the AI Studio API.
+func (e *AIStudioExecutor) countTokensNative(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
 	baseModel := thinking.ParseSuffix(req.Model).ModelName
 	_, body, err := e.translateRequest(req, opts, false)
 	if err != nil {
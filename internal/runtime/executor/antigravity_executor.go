@@ -22,7 +22,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/agentdebug"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/cache"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/config"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/misc"
@@ -30,6 +32,7 @@ import (
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/runtime/executor/helps"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/thinking"
 	antigravityclaude "github.com/router-for-me/CLIProxyAPI/v6/internal/translator/antigravity/claude"
+	translatorcommon "github.com/router-for-me/CLIProxyAPI/v6/internal/translator/common"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/util"
 	sdkAuth "github.com/router-for-me/CLIProxyAPI/v6/sdk/auth"
 	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/auth"
@@ -609,6 +612,7 @@ attemptLoop:
 
 			if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode >= http.StatusMultipleChoices {
 				log.Debugf("antigravity executor: upstream error status: %d, body: %s", httpResp.StatusCode, helps.SummarizeErrorBody(httpResp.Header.Get("Content-Type"), bodyBytes))
+				agentdebug.Log("", "antigravity.Execute", "upstream error", map[string]any{"status": httpResp.StatusCode, "model": baseModel, "base_url": baseURL, "attempt": attempt, "body": helps.SummarizeErrorBody(httpResp.Header.Get("Content-Type"), bodyBytes)})
 				lastStatus = httpResp.StatusCode
 				lastBody = append([]byte(nil), bodyBytes...)
 				lastErr = nil
@@ -820,6 +824,7 @@ attemptLoop:
 					}
 				}
 
+				agentdebug.Log("", "antigravity.executeClaudeNonStream", "upstream error", map[string]any{"status": httpResp.StatusCode, "model": baseModel, "base_url": baseURL, "attempt": attempt, "body": helps.SummarizeErrorBody(httpResp.Header.Get("Content-Type"), bodyBytes)})
 				lastStatus = httpResp.StatusCode
 				lastBody = append([]byte(nil), bodyBytes...)
 				lastErr = nil
@@ -1141,6 +1146,9 @@ func (e *AntigravityExecutor) ExecuteStream(ctx context.Context, auth *cliproxya
 	baseModel := thinking.ParseSuffix(req.Model).ModelName
 
 	ctx = context.WithValue(ctx, "alt", "")
+	if antigravitySuppressThoughts(ctx, e.cfg) {
+		ctx = translatorcommon.WithSuppressedThoughts(ctx)
+	}
 	if inCooldown, remaining := antigravityIsInShortCooldown(auth, baseModel, time.Now()); inCooldown && !antigravityShouldBypassShortCooldown(ctx, e.cfg) {
 		log.Debugf("antigravity executor: auth %s in short cooldown for model %s (%s remaining), returning 429 to switch auth", auth.ID, baseModel, remaining)
 		d := remaining
@@ -1280,6 +1288,7 @@ attemptLoop:
 					}
 				}
 
+				agentdebug.Log("", "antigravity.ExecuteStream", "upstream error", map[string]any{"status": httpResp.StatusCode, "model": baseModel, "base_url": baseURL, "attempt": attempt, "body": helps.SummarizeErrorBody(httpResp.Header.Get("Content-Type"), bodyBytes)})
 				lastStatus = httpResp.StatusCode
 				lastBody = append([]byte(nil), bodyBytes...)
 				lastErr = nil
@@ -1401,8 +1410,17 @@ func (e *AntigravityExecutor) Refresh(ctx context.Context, auth *cliproxyauth.Au
 	return updated, nil
 }
 
-// CountTokens counts tokens for the given request using the Antigravity API.
+// CountTokens counts tokens for the given request, preferring the Antigravity counting endpoint.
+// Identical requests are served from cache and a local estimate is used when the
+// endpoint is unavailable.
 func (e *AntigravityExecutor) CountTokens(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
+	return helps.CountTokensPreferNative(ctx, e.Identifier(), req, opts, func() (cliproxyexecutor.Response, error) {
+		return e.countTokensNative(ctx, auth, req, opts)
+	})
+}
+
+// countTokensNative counts tokens using the Antigravity API.
+func (e *AntigravityExecutor) countTokensNative(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
 	baseModel := thinking.ParseSuffix(req.Model).ModelName
 
 	from := opts.SourceFormat
@@ -2166,6 +2184,19 @@ func antigravityShouldRetrySoftRateLimit(statusCode int, body []byte) bool {
 	return decideAntigravity429(body).kind == antigravity429DecisionSoftRetry
 }
 
+// antigravitySuppressThoughts reports whether thought parts should be stripped from the
+// streamed response. The X-ProxyPilot-Suppress-Thoughts request header overrides the config.
+func antigravitySuppressThoughts(ctx context.Context, cfg *config.Config) bool {
+	if ginCtx, ok := ctx.Value("gin").(*gin.Context); ok && ginCtx != nil && ginCtx.Request != nil {
+		if v := strings.TrimSpace(ginCtx.Request.Header.Get("X-ProxyPilot-Suppress-Thoughts")); v != "" {
+			if enabled, err := strconv.ParseBool(v); err == nil {
+				return enabled
+			}
+		}
+	}
+	return cfg != nil && cfg.AntigravitySuppressThoughts
+}
+
 func antigravityShouldBypassShortCooldown(ctx context.Context, cfg *config.Config) bool {
 	return cliproxyauth.AntigravityCreditsRequested(ctx) && antigravityCreditsRetryEnabled(cfg)
 }
@@ -1144,6 +1144,58 @@ func TestClaudeExecutor_CountTokens_AppliesCacheControlGuards(t *testing.T) {
 	}
 }
 
+func TestClaudeExecutor_CountTokensCachesAndFallsBack(t *testing.T) {
+	hits := 0
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"input_tokens":42}`))
+	}))
+	defer server.Close()
+
+	executor := NewClaudeExecutor(&config.Config{})
+	auth := &cliproxyauth.Auth{Attributes: map[string]string{
+		"api_key":  "key-123",
+		"base_url": server.URL,
+	}}
+	count := func(text string) ([]byte, error) {
+		resp, err := executor.CountTokens(context.Background(), auth, cliproxyexecutor.Request{
+			Model:   "claude-sonnet-4-5-20250929",
+			Payload: []byte(`{"model":"claude-sonnet-4-5-20250929","messages":[{"role":"user","content":"` + text + `"}]}`),
+		}, cliproxyexecutor.Options{SourceFormat: sdktranslator.FromString("claude")})
+		return resp.Payload, err
+	}
+
+	for i := 0; i < 2; i++ {
+		out, err := count("cache me")
+		if err != nil {
+			t.Fatalf("CountTokens error: %v", err)
+		}
+		if got := gjson.GetBytes(out, "input_tokens").Int(); got != 42 {
+			t.Fatalf("input_tokens = %d, want 42", got)
+		}
+	}
+	if hits != 1 {
+		t.Fatalf("upstream hits = %d, want 1 for identical payloads", hits)
+	}
+
+	status = http.StatusNotFound
+	out, err := count("count me locally")
+	if err != nil {
+		t.Fatalf("expected local fallback, got error: %v", err)
+	}
+	if got := gjson.GetBytes(out, "input_tokens").Int(); got <= 0 || got == 42 {
+		t.Fatalf("expected local estimate, got %s", out)
+	}
+
+	status = http.StatusBadRequest
+	if _, err = count("invalid request"); err == nil {
+		t.Fatal("expected 400 from the counting endpoint to be returned")
+	}
+}
+
 func hasTTLOrderingViolation(payload []byte) bool {
 	seen5m := false
 	violates := false
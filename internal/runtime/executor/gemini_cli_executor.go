@@ -464,8 +464,17 @@ func (e *GeminiCLIExecutor) ExecuteStream(ctx context.Context, auth *cliproxyaut
 	return nil, err
 }
 
-// CountTokens counts tokens for the given request using the Gemini CLI API.
+// CountTokens counts tokens for the given request, preferring the Gemini CLI counting endpoint.
+// Identical requests are served from cache and a local estimate is used when the
+// endpoint is unavailable.
 func (e *GeminiCLIExecutor) CountTokens(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
+	return helps.CountTokensPreferNative(ctx, e.Identifier(), req, opts, func() (cliproxyexecutor.Response, error) {
+		return e.countTokensNative(ctx, auth, req, opts)
+	})
+}
+
+// countTokensNative counts tokens using the Gemini CLI API.
+func (e *GeminiCLIExecutor) countTokensNative(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
 	baseModel := thinking.ParseSuffix(req.Model).ModelName
 
 	tokenSource, baseTokenData, err := prepareGeminiCLITokenSource(ctx, e.cfg, auth)
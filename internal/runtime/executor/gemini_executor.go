@@ -340,8 +340,17 @@ func (e *GeminiExecutor) ExecuteStream(ctx context.Context, auth *cliproxyauth.A
 	return &cliproxyexecutor.StreamResult{Headers: httpResp.Header.Clone(), Chunks: out}, nil
 }
 
-// CountTokens counts tokens for the given request using the Gemini API.
+// CountTokens counts tokens for the given request, preferring the Gemini counting endpoint.
+// Identical requests are served from cache and a local estimate is used when the
+// endpoint is unavailable.
 func (e *GeminiExecutor) CountTokens(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
+	return helps.CountTokensPreferNative(ctx, e.Identifier(), req, opts, func() (cliproxyexecutor.Response, error) {
+		return e.countTokensNative(ctx, auth, req, opts)
+	})
+}
+
+// countTokensNative counts tokens using the Gemini API.
+func (e *GeminiExecutor) countTokensNative(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
 	baseModel := thinking.ParseSuffix(req.Model).ModelName
 
 	apiKey, bearer := geminiCreds(auth)
@@ -0,0 +1,461 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/config"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/registry"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/runtime/executor/helps"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/thinking"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/util"
+	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/auth"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/executor"
+	"github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/usage"
+	sdktranslator "github.com/router-for-me/CLIProxyAPI/v6/sdk/translator"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	groqDefaultBaseURL = "https://api.groq.com/openai/v1"
+	groqModelCacheTTL  = 30 * time.Minute
+)
+
+// GroqExecutor is a stateless executor for Groq API keys. Groq speaks the OpenAI chat
+// completions protocol but enforces strict per-key request and token rate limits, which
+// are reported through x-ratelimit-* headers and surfaced here as cooldown hints.
+type GroqExecutor struct {
+	cfg *config.Config
+}
+
+// NewGroqExecutor creates a new Groq executor.
+func NewGroqExecutor(cfg *config.Config) *GroqExecutor { return &GroqExecutor{cfg: cfg} }
+
+// Identifier implements cliproxyauth.ProviderExecutor.
+func (e *GroqExecutor) Identifier() string { return "groq" }
+
+// PrepareRequest injects Groq credentials into the outgoing HTTP request.
+func (e *GroqExecutor) PrepareRequest(req *http.Request, auth *cliproxyauth.Auth) error {
+	if req == nil {
+		return nil
+	}
+	_, apiKey := groqCredentials(auth)
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	var attrs map[string]string
+	if auth != nil {
+		attrs = auth.Attributes
+	}
+	util.ApplyCustomHeadersFromAttrs(req, attrs)
+	return nil
+}
+
+// HttpRequest injects Groq credentials into the request and executes it.
+func (e *GroqExecutor) HttpRequest(ctx context.Context, auth *cliproxyauth.Auth, req *http.Request) (*http.Response, error) {
+	if req == nil {
+		return nil, fmt.Errorf("groq executor: request is nil")
+	}
+	if ctx == nil {
+		ctx = req.Context()
+	}
+	httpReq := req.WithContext(ctx)
+	if err := e.PrepareRequest(httpReq, auth); err != nil {
+		return nil, err
+	}
+	httpClient := helps.NewProxyAwareHTTPClient(ctx, e.cfg, auth, 0)
+	return httpClient.Do(httpReq)
+}
+
+func (e *GroqExecutor) Execute(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (resp cliproxyexecutor.Response, err error) {
+	baseModel := thinking.ParseSuffix(req.Model).ModelName
+
+	reporter := helps.NewUsageReporter(ctx, e.Identifier(), baseModel, auth)
+	defer reporter.TrackFailure(ctx, &err)
+
+	from := opts.SourceFormat
+	to := sdktranslator.FromString("openai")
+	translated, err := e.translateRequest(req, opts, baseModel, false)
+	if err != nil {
+		return resp, err
+	}
+
+	httpResp, err := e.doRequest(ctx, auth, translated, false)
+	if err != nil {
+		return resp, err
+	}
+	defer func() {
+		if errClose := httpResp.Body.Close(); errClose != nil {
+			log.Errorf("groq executor: close response body error: %v", errClose)
+		}
+	}()
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		helps.RecordAPIResponseError(ctx, e.cfg, err)
+		return resp, err
+	}
+	helps.AppendAPIResponseChunk(ctx, e.cfg, body)
+	reporter.Publish(ctx, helps.ParseOpenAIUsage(body))
+	reporter.EnsurePublished(ctx)
+	var param any
+	out := sdktranslator.TranslateNonStream(ctx, to, from, req.Model, opts.OriginalRequest, translated, body, &param)
+	resp = cliproxyexecutor.Response{Payload: out, Headers: httpResp.Header.Clone()}
+	return resp, nil
+}
+
+func (e *GroqExecutor) ExecuteStream(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (_ *cliproxyexecutor.StreamResult, err error) {
+	baseModel := thinking.ParseSuffix(req.Model).ModelName
+
+	reporter := helps.NewUsageReporter(ctx, e.Identifier(), baseModel, auth)
+	defer reporter.TrackFailure(ctx, &err)
+
+	from := opts.SourceFormat
+	to := sdktranslator.FromString("openai")
+	translated, err := e.translateRequest(req, opts, baseModel, true)
+	if err != nil {
+		return nil, err
+	}
+	translated, _ = sjson.SetBytes(translated, "stream_options.include_usage", true)
+
+	httpResp, err := e.doRequest(ctx, auth, translated, true)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan cliproxyexecutor.StreamChunk)
+	go func() {
+		defer close(out)
+		defer func() {
+			if errClose := httpResp.Body.Close(); errClose != nil {
+				log.Errorf("groq executor: close response body error: %v", errClose)
+			}
+		}()
+		scanner := bufio.NewScanner(httpResp.Body)
+		scanner.Buffer(nil, 52_428_800) // 50MB
+		var param any
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			helps.AppendAPIResponseChunk(ctx, e.cfg, line)
+			if detail, ok := parseGroqStreamUsage(line); ok {
+				reporter.Publish(ctx, detail)
+			}
+			if !bytes.HasPrefix(line, []byte("data:")) {
+				continue
+			}
+			chunks := sdktranslator.TranslateStream(ctx, to, from, req.Model, opts.OriginalRequest, translated, bytes.Clone(line), &param)
+			for i := range chunks {
+				out <- cliproxyexecutor.StreamChunk{Payload: chunks[i]}
+			}
+		}
+		if errScan := scanner.Err(); errScan != nil {
+			helps.RecordAPIResponseError(ctx, e.cfg, errScan)
+			reporter.PublishFailure(ctx)
+			out <- cliproxyexecutor.StreamChunk{Err: errScan}
+		} else {
+			chunks := sdktranslator.TranslateStream(ctx, to, from, req.Model, opts.OriginalRequest, translated, []byte("data: [DONE]"), &param)
+			for i := range chunks {
+				out <- cliproxyexecutor.StreamChunk{Payload: chunks[i]}
+			}
+		}
+		reporter.EnsurePublished(ctx)
+	}()
+	return &cliproxyexecutor.StreamResult{Headers: httpResp.Header.Clone(), Chunks: out}, nil
+}
+
+// CountTokens counts tokens locally; Groq has no token counting endpoint.
+func (e *GroqExecutor) CountTokens(ctx context.Context, auth *cliproxyauth.Auth, req cliproxyexecutor.Request, opts cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
+	return helps.LocalTokenCount(ctx, req.Model, opts.SourceFormat, req.Payload)
+}
+
+// Refresh is a no-op for API-key based providers.
+func (e *GroqExecutor) Refresh(ctx context.Context, auth *cliproxyauth.Auth) (*cliproxyauth.Auth, error) {
+	_ = ctx
+	return auth, nil
+}
+
+func (e *GroqExecutor) translateRequest(req cliproxyexecutor.Request, opts cliproxyexecutor.Options, baseModel string, stream bool) ([]byte, error) {
+	from := opts.SourceFormat
+	to := sdktranslator.FromString("openai")
+	originalPayload := req.Payload
+	if len(opts.OriginalRequest) > 0 {
+		originalPayload = opts.OriginalRequest
+	}
+	originalTranslated := sdktranslator.TranslateRequest(from, to, baseModel, originalPayload, stream)
+	translated := sdktranslator.TranslateRequest(from, to, baseModel, req.Payload, stream)
+	requestedModel := helps.PayloadRequestedModel(opts, req.Model)
+	requestPath := helps.PayloadRequestPath(opts)
+	translated = helps.ApplyPayloadConfigWithRoot(e.cfg, baseModel, to.String(), "", translated, originalTranslated, requestedModel, requestPath)
+	return thinking.ApplyThinking(translated, req.Model, from.String(), to.String(), e.Identifier())
+}
+
+// doRequest posts payload to the chat completions endpoint. Non-2xx responses are
+// returned as statusErr carrying the rate-limit reset as a retry hint.
+func (e *GroqExecutor) doRequest(ctx context.Context, auth *cliproxyauth.Auth, payload []byte, stream bool) (*http.Response, error) {
+	baseURL, apiKey := groqCredentials(auth)
+	if apiKey == "" {
+		return nil, statusErr{code: http.StatusUnauthorized, msg: "missing groq api key"}
+	}
+	url := baseURL + "/chat/completions"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	httpReq.Header.Set("User-Agent", "cli-proxy-groq")
+	var attrs map[string]string
+	if auth != nil {
+		attrs = auth.Attributes
+	}
+	util.ApplyCustomHeadersFromAttrs(httpReq, attrs)
+	if stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+		httpReq.Header.Set("Cache-Control", "no-cache")
+	}
+	var authID, authLabel, authType, authValue string
+	if auth != nil {
+		authID = auth.ID
+		authLabel = auth.Label
+		authType, authValue = auth.AccountInfo()
+	}
+	helps.RecordAPIRequest(ctx, e.cfg, helps.UpstreamRequestLog{
+		URL:       url,
+		Method:    http.MethodPost,
+		Headers:   httpReq.Header.Clone(),
+		Body:      payload,
+		Provider:  e.Identifier(),
+		AuthID:    authID,
+		AuthLabel: authLabel,
+		AuthType:  authType,
+		AuthValue: authValue,
+	})
+
+	httpClient := helps.NewProxyAwareHTTPClient(ctx, e.cfg, auth, 0)
+	httpResp, err := httpClient.Do(httpReq)
+	if err != nil {
+		helps.RecordAPIResponseError(ctx, e.cfg, err)
+		return nil, err
+	}
+	helps.RecordAPIResponseMetadata(ctx, e.cfg, httpResp.StatusCode, httpResp.Header.Clone())
+	if httpResp.StatusCode >= 200 && httpResp.StatusCode < 300 {
+		return httpResp, nil
+	}
+	b, _ := io.ReadAll(httpResp.Body)
+	if errClose := httpResp.Body.Close(); errClose != nil {
+		log.Errorf("groq executor: close response body error: %v", errClose)
+	}
+	helps.AppendAPIResponseChunk(ctx, e.cfg, b)
+	helps.LogWithRequestID(ctx).Debugf("request error, error status: %d, error message: %s", httpResp.StatusCode, helps.SummarizeErrorBody(httpResp.Header.Get("Content-Type"), b))
+	errStatus := statusErr{code: httpResp.StatusCode, msg: string(b)}
+	if httpResp.StatusCode == http.StatusTooManyRequests {
+		errStatus.retryAfter = parseGroqRetryAfter(httpResp.Header, time.Now())
+	}
+	return nil, errStatus
+}
+
+// parseGroqRetryAfter derives the cooldown for a rate-limited Groq key. Retry-After wins
+// when present; otherwise the reset of whichever limit is exhausted is used, falling back
+// to the later of the request and token resets.
+func parseGroqRetryAfter(header http.Header, now time.Time) *time.Duration {
+	if d := parseRetryAfterHeader(header, now); d != nil {
+		return d
+	}
+	resetRequests := parseGroqResetDuration(header.Get("x-ratelimit-reset-requests"))
+	resetTokens := parseGroqResetDuration(header.Get("x-ratelimit-reset-tokens"))
+	var d time.Duration
+	switch {
+	case strings.TrimSpace(header.Get("x-ratelimit-remaining-requests")) == "0" && resetRequests > 0:
+		d = resetRequests
+	case strings.TrimSpace(header.Get("x-ratelimit-remaining-tokens")) == "0" && resetTokens > 0:
+		d = resetTokens
+	default:
+		d = max(resetRequests, resetTokens)
+	}
+	if d <= 0 {
+		return nil
+	}
+	return &d
+}
+
+// parseGroqResetDuration parses reset values such as "2m59.56s" or "7.66s".
+func parseGroqResetDuration(raw string) time.Duration {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// parseGroqStreamUsage reads usage from a stream line. Groq reports streaming usage
+// under x_groq.usage rather than the top-level usage field.
+func parseGroqStreamUsage(line []byte) (detail usage.Detail, ok bool) {
+	if detail, ok = helps.ParseOpenAIStreamUsage(line); ok {
+		return detail, true
+	}
+	payload := bytes.TrimSpace(bytes.TrimPrefix(bytes.TrimSpace(line), []byte("data:")))
+	usageNode := gjson.GetBytes(payload, "x_groq.usage")
+	if !usageNode.Exists() {
+		return detail, false
+	}
+	return helps.ParseOpenAIStreamUsage([]byte(`{"usage":` + usageNode.Raw + `}`))
+}
+
+func groqCredentials(auth *cliproxyauth.Auth) (baseURL, apiKey string) {
+	if auth != nil && auth.Attributes != nil {
+		baseURL = strings.TrimSpace(auth.Attributes["base_url"])
+		apiKey = strings.TrimSpace(auth.Attributes["api_key"])
+	}
+	if baseURL == "" {
+		baseURL = groqDefaultBaseURL
+	}
+	return strings.TrimSuffix(baseURL, "/"), apiKey
+}
+
+type groqModelCacheEntry struct {
+	models    []*registry.ModelInfo
+	fetchedAt time.Time
+}
+
+var (
+	groqModelCacheMu sync.Mutex
+	groqModelCache   = map[string]groqModelCacheEntry{}
+)
+
+func groqModelCacheKey(auth *cliproxyauth.Auth) string {
+	baseURL, apiKey := groqCredentials(auth)
+	sum := sha256.Sum256([]byte(apiKey))
+	return baseURL + "|" + hex.EncodeToString(sum[:8])
+}
+
+// CachedGroqModels returns the models last discovered for auth, or nil when discovery has
+// not completed yet. Stale entries are still returned so registration never goes empty
+// while a refresh is pending.
+func CachedGroqModels(auth *cliproxyauth.Auth) []*registry.ModelInfo {
+	groqModelCacheMu.Lock()
+	defer groqModelCacheMu.Unlock()
+	entry, ok := groqModelCache[groqModelCacheKey(auth)]
+	if !ok {
+		return nil
+	}
+	return cloneGroqModels(entry.models)
+}
+
+// FetchGroqModels discovers the chat models available to auth from the Groq /models
+// listing. Results are cached per key for groqModelCacheTTL.
+func FetchGroqModels(ctx context.Context, cfg *config.Config, auth *cliproxyauth.Auth) ([]*registry.ModelInfo, error) {
+	key := groqModelCacheKey(auth)
+	groqModelCacheMu.Lock()
+	if entry, ok := groqModelCache[key]; ok && time.Since(entry.fetchedAt) < groqModelCacheTTL {
+		groqModelCacheMu.Unlock()
+		return cloneGroqModels(entry.models), nil
+	}
+	groqModelCacheMu.Unlock()
+
+	baseURL, apiKey := groqCredentials(auth)
+	if apiKey == "" {
+		return nil, fmt.Errorf("groq executor: missing api key")
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	httpReq.Header.Set("User-Agent", "cli-proxy-groq")
+	var attrs map[string]string
+	if auth != nil {
+		attrs = auth.Attributes
+	}
+	util.ApplyCustomHeadersFromAttrs(httpReq, attrs)
+
+	httpClient := helps.NewProxyAwareHTTPClient(ctx, cfg, auth, 30*time.Second)
+	httpResp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if errClose := httpResp.Body.Close(); errClose != nil {
+			log.Errorf("groq executor: close response body error: %v", errClose)
+		}
+	}()
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		return nil, statusErr{code: httpResp.StatusCode, msg: string(body)}
+	}
+
+	models := parseGroqModels(body)
+	groqModelCacheMu.Lock()
+	groqModelCache[key] = groqModelCacheEntry{models: models, fetchedAt: time.Now()}
+	groqModelCacheMu.Unlock()
+	return cloneGroqModels(models), nil
+}
+
+// parseGroqModels converts a /models listing into registry models, skipping inactive
+// entries and the speech models that cannot serve chat completions.
+func parseGroqModels(body []byte) []*registry.ModelInfo {
+	now := time.Now().Unix()
+	var models []*registry.ModelInfo
+	for _, item := range gjson.GetBytes(body, "data").Array() {
+		id := strings.TrimSpace(item.Get("id").String())
+		if id == "" {
+			continue
+		}
+		if active := item.Get("active"); active.Exists() && !active.Bool() {
+			continue
+		}
+		lower := strings.ToLower(id)
+		if strings.Contains(lower, "whisper") || strings.Contains(lower, "tts") {
+			continue
+		}
+		created := item.Get("created").Int()
+		if created == 0 {
+			created = now
+		}
+		ownedBy := strings.TrimSpace(item.Get("owned_by").String())
+		if ownedBy == "" {
+			ownedBy = "groq"
+		}
+		models = append(models, &registry.ModelInfo{
+			ID:                  id,
+			Object:              "model",
+			Created:             created,
+			OwnedBy:             ownedBy,
+			Type:                "groq",
+			DisplayName:         id,
+			ContextLength:       int(item.Get("context_window").Int()),
+			MaxCompletionTokens: int(item.Get("max_completion_tokens").Int()),
+		})
+	}
+	return models
+}
+
+func cloneGroqModels(models []*registry.ModelInfo) []*registry.ModelInfo {
+	if models == nil {
+		return nil
+	}
+	out := make([]*registry.ModelInfo, 0, len(models))
+	for _, m := range models {
+		if m == nil {
+			continue
+		}
+		clone := *m
+		out = append(out, &clone)
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/config"
+	cliproxyauth "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/auth"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/executor"
+	sdktranslator "github.com/router-for-me/CLIProxyAPI/v6/sdk/translator"
+)
+
+func TestParseGroqRetryAfter(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{
+			name:   "retry-after wins",
+			header: http.Header{"Retry-After": {"3"}, "X-Ratelimit-Reset-Tokens": {"20s"}},
+			want:   3 * time.Second,
+		},
+		{
+			name: "exhausted requests",
+			header: http.Header{
+				"X-Ratelimit-Remaining-Requests": {"0"},
+				"X-Ratelimit-Reset-Requests":     {"2m59.56s"},
+				"X-Ratelimit-Remaining-Tokens":   {"100"},
+				"X-Ratelimit-Reset-Tokens":       {"1s"},
+			},
+			want: 2*time.Minute + 59560*time.Millisecond,
+		},
+		{
+			name: "exhausted tokens",
+			header: http.Header{
+				"X-Ratelimit-Remaining-Requests": {"10"},
+				"X-Ratelimit-Reset-Requests":     {"1m"},
+				"X-Ratelimit-Remaining-Tokens":   {"0"},
+				"X-Ratelimit-Reset-Tokens":       {"7.66s"},
+			},
+			want: 7660 * time.Millisecond,
+		},
+		{
+			name:   "later reset when unknown",
+			header: http.Header{"X-Ratelimit-Reset-Requests": {"2s"}, "X-Ratelimit-Reset-Tokens": {"5s"}},
+			want:   5 * time.Second,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseGroqRetryAfter(tc.header, now)
+			if got == nil || *got != tc.want {
+				t.Fatalf("retry after = %v, want %v", got, tc.want)
+			}
+		})
+	}
+	if got := parseGroqRetryAfter(http.Header{}, now); got != nil {
+		t.Fatalf("expected nil retry after, got %v", *got)
+	}
+}
+
+func TestGroqExecutorRateLimitReturnsRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer gsk_test" {
+			t.Errorf("authorization = %q", got)
+		}
+		w.Header().Set("x-ratelimit-remaining-requests", "0")
+		w.Header().Set("x-ratelimit-reset-requests", "12s")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"message":"rate limit reached"}}`))
+	}))
+	defer server.Close()
+
+	exec := NewGroqExecutor(&config.Config{})
+	auth := &cliproxyauth.Auth{Attributes: map[string]string{"base_url": server.URL, "api_key": "gsk_test"}}
+	_, err := exec.Execute(context.Background(), auth, cliproxyexecutor.Request{
+		Model:   "llama-3.3-70b-versatile",
+		Payload: []byte(`{"model":"llama-3.3-70b-versatile","messages":[{"role":"user","content":"hi"}]}`),
+	}, cliproxyexecutor.Options{SourceFormat: sdktranslator.FromString("openai")})
+	se, ok := err.(statusErr)
+	if !ok {
+		t.Fatalf("expected statusErr, got %T: %v", err, err)
+	}
+	if se.StatusCode() != http.StatusTooManyRequests || se.RetryAfter() == nil || *se.RetryAfter() != 12*time.Second {
+		t.Fatalf("unexpected error: code=%d retryAfter=%v", se.StatusCode(), se.RetryAfter())
+	}
+}
+
+func TestFetchGroqModelsFiltersAndCaches(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/models" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":[` +
+			`{"id":"llama-3.3-70b-versatile","owned_by":"Meta","active":true,"context_window":131072},` +
+			`{"id":"whisper-large-v3","owned_by":"OpenAI","active":true},` +
+			`{"id":"old-model","owned_by":"Meta","active":false}]}`))
+	}))
+	defer server.Close()
+
+	auth := &cliproxyauth.Auth{Attributes: map[string]string{"base_url": server.URL, "api_key": "gsk_models"}}
+	if CachedGroqModels(auth) != nil {
+		t.Fatal("expected empty cache before discovery")
+	}
+	models, err := FetchGroqModels(context.Background(), &config.Config{}, auth)
+	if err != nil {
+		t.Fatalf("FetchGroqModels error: %v", err)
+	}
+	if len(models) != 1 || models[0].ID != "llama-3.3-70b-versatile" || models[0].ContextLength != 131072 {
+		t.Fatalf("unexpected models: %+v", models)
+	}
+	if _, err = FetchGroqModels(context.Background(), &config.Config{}, auth); err != nil {
+		t.Fatalf("cached FetchGroqModels error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected one upstream call, got %d", calls)
+	}
+	if cached := CachedGroqModels(auth); len(cached) != 1 {
+		t.Fatalf("expected cached models, got %+v", cached)
+	}
+}
@@ -0,0 +1,148 @@
+package helps
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/thinking"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/executor"
+	sdktranslator "github.com/router-for-me/CLIProxyAPI/v6/sdk/translator"
+)
+
+type tokenCountCacheEntry struct {
+	payload []byte
+	expire  time.Time
+}
+
+var (
+	tokenCountCache   = make(map[string]tokenCountCacheEntry)
+	tokenCountCacheMu sync.Mutex
+)
+
+const (
+	tokenCountCacheTTL        = 10 * time.Minute
+	tokenCountCacheMaxEntries = 1024
+)
+
+// TokenCountCacheKey identifies a count request by provider, model, source format and
+// payload, so identical requests reuse one upstream count.
+func TokenCountCacheKey(provider, model, format, alt string, payload []byte) string {
+	h := sha256.New()
+	for _, part := range []string{provider, model, format, alt} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CachedTokenCount returns the translated count response stored for key, if still fresh.
+func CachedTokenCount(key string) ([]byte, bool) {
+	tokenCountCacheMu.Lock()
+	defer tokenCountCacheMu.Unlock()
+	entry, ok := tokenCountCache[key]
+	if !ok {
+		return nil, false
+	}
+	if !entry.expire.After(time.Now()) {
+		delete(tokenCountCache, key)
+		return nil, false
+	}
+	return append([]byte(nil), entry.payload...), true
+}
+
+// StoreTokenCount caches a translated count response under key.
+func StoreTokenCount(key string, payload []byte) {
+	if key == "" || len(payload) == 0 {
+		return
+	}
+	now := time.Now()
+	tokenCountCacheMu.Lock()
+	defer tokenCountCacheMu.Unlock()
+	if len(tokenCountCache) >= tokenCountCacheMaxEntries {
+		purgeTokenCountsLocked(now)
+	}
+	tokenCountCache[key] = tokenCountCacheEntry{payload: append([]byte(nil), payload...), expire: now.Add(tokenCountCacheTTL)}
+}
+
+// purgeTokenCountsLocked drops expired entries, and the entries closest to expiry when the
+// cache is still full afterwards.
+func purgeTokenCountsLocked(now time.Time) {
+	for key, entry := range tokenCountCache {
+		if !entry.expire.After(now) {
+			delete(tokenCountCache, key)
+		}
+	}
+	for len(tokenCountCache) >= tokenCountCacheMaxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for key, entry := range tokenCountCache {
+			if oldestKey == "" || entry.expire.Before(oldest) {
+				oldestKey, oldest = key, entry.expire
+			}
+		}
+		delete(tokenCountCache, oldestKey)
+	}
+}
+
+// CountTokensPreferNative answers identical count requests from cache, otherwise asks the
+// provider's native counting endpoint through native and falls back to a local tiktoken
+// estimate when that endpoint is unavailable. Malformed requests and cancellations are
+// returned as errors rather than estimated.
+func CountTokensPreferNative(ctx context.Context, provider string, req cliproxyexecutor.Request, opts cliproxyexecutor.Options, native func() (cliproxyexecutor.Response, error)) (cliproxyexecutor.Response, error) {
+	key := TokenCountCacheKey(provider, req.Model, opts.SourceFormat.String(), opts.Alt, req.Payload)
+	if payload, ok := CachedTokenCount(key); ok {
+		return cliproxyexecutor.Response{Payload: payload}, nil
+	}
+	resp, err := native()
+	if err == nil {
+		StoreTokenCount(key, resp.Payload)
+		return resp, nil
+	}
+	if !nativeCountUnavailable(err) {
+		return resp, err
+	}
+	local, errLocal := LocalTokenCount(ctx, req.Model, opts.SourceFormat, req.Payload)
+	if errLocal != nil {
+		return resp, err
+	}
+	LogWithRequestID(ctx).Debugf("%s: native token count unavailable (%v), using local estimate", provider, err)
+	return local, nil
+}
+
+// nativeCountUnavailable reports whether err means the counting endpoint could not answer,
+// as opposed to the request itself being invalid or cancelled.
+func nativeCountUnavailable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var se interface{ StatusCode() int }
+	if errors.As(err, &se) && se.StatusCode() == http.StatusBadRequest {
+		return false
+	}
+	return true
+}
+
+// LocalTokenCount estimates prompt tokens with tiktoken and returns the count in the
+// response shape of the source format.
+func LocalTokenCount(ctx context.Context, model string, from sdktranslator.Format, payload []byte) (cliproxyexecutor.Response, error) {
+	baseModel := thinking.ParseSuffix(model).ModelName
+	to := sdktranslator.FromString("openai")
+	body := sdktranslator.TranslateRequest(from, to, baseModel, payload, false)
+
+	enc, err := TokenizerForModel(baseModel)
+	if err != nil {
+		return cliproxyexecutor.Response{}, err
+	}
+	count, err := CountOpenAIChatTokens(enc, body)
+	if err != nil {
+		return cliproxyexecutor.Response{}, err
+	}
+	translated := sdktranslator.TranslateTokenCount(ctx, to, from, count, BuildOpenAIUsageJSON(count))
+	return cliproxyexecutor.Response{Payload: translated}, nil
+}
@@ -36,12 +36,14 @@ var levelToBudgetMap = map[string]int{
 //   - xhigh   → 32768
 //   - max     → 128000
 //
+// Global overrides installed via SetLevelBudgets take precedence over these defaults.
+// Use ConvertLevelToBudgetForModel when the target model is known.
+//
 // Returns:
 //   - budget: The converted budget value
 //   - ok: true if level is valid, false otherwise
 func ConvertLevelToBudget(level string) (int, bool) {
-	budget, ok := levelToBudgetMap[strings.ToLower(level)]
-	return budget, ok
+	return ConvertLevelToBudgetForModel("", level)
 }
 
 // BudgetThreshold constants define the upper bounds for each thinking level.
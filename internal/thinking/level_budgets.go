@@ -0,0 +1,103 @@
+package thinking
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// LevelBudgetRule overrides level → budget mappings for models matching Name.
+type LevelBudgetRule struct {
+	// Name is the model name or wildcard pattern (e.g., "gemini-2.5-*"). Matching is case-insensitive.
+	Name string
+	// Budgets maps thinking levels to budgets for matching models.
+	Budgets map[string]int
+}
+
+// levelBudgetTable holds the configured overrides layered on top of levelToBudgetMap.
+type levelBudgetTable struct {
+	global map[string]int
+	rules  []LevelBudgetRule
+}
+
+var levelBudgets atomic.Pointer[levelBudgetTable]
+
+// SetLevelBudgets installs configured level → budget overrides.
+//
+// global replaces entries of the default table for every model; rules apply to matching
+// models only, and the first rule that matches the model and defines the level wins.
+// Levels missing from both fall back to the defaults. Passing nil values restores defaults.
+func SetLevelBudgets(global map[string]int, rules []LevelBudgetRule) {
+	table := &levelBudgetTable{global: normalizeLevelBudgets(global)}
+	for _, rule := range rules {
+		name := strings.ToLower(strings.TrimSpace(rule.Name))
+		budgets := normalizeLevelBudgets(rule.Budgets)
+		if name == "" || len(budgets) == 0 {
+			continue
+		}
+		table.rules = append(table.rules, LevelBudgetRule{Name: name, Budgets: budgets})
+	}
+	if len(table.global) == 0 && len(table.rules) == 0 {
+		levelBudgets.Store(nil)
+		return
+	}
+	levelBudgets.Store(table)
+}
+
+// ConvertLevelToBudgetForModel converts a thinking level to a budget, honoring
+// per-model overrides installed via SetLevelBudgets.
+func ConvertLevelToBudgetForModel(model, level string) (int, bool) {
+	level = strings.ToLower(strings.TrimSpace(level))
+	if table := levelBudgets.Load(); table != nil {
+		if model = strings.ToLower(strings.TrimSpace(model)); model != "" {
+			for _, rule := range table.rules {
+				if budget, ok := rule.Budgets[level]; ok && matchLevelRule(rule.Name, model) {
+					return budget, true
+				}
+			}
+		}
+		if budget, ok := table.global[level]; ok {
+			return budget, true
+		}
+	}
+	budget, ok := levelToBudgetMap[level]
+	return budget, ok
+}
+
+func normalizeLevelBudgets(in map[string]int) map[string]int {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]int, len(in))
+	for level, budget := range in {
+		level = strings.ToLower(strings.TrimSpace(level))
+		// Only known levels can be remapped; budgets below -1 are invalid.
+		if _, known := levelToBudgetMap[level]; !known || budget < -1 {
+			continue
+		}
+		out[level] = budget
+	}
+	return out
+}
+
+// matchLevelRule performs glob matching where '*' matches zero or more characters.
+func matchLevelRule(pattern, model string) bool {
+	if pattern == "*" || pattern == model {
+		return true
+	}
+	if !strings.Contains(pattern, "*") {
+		return false
+	}
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(model, parts[0]) {
+		return false
+	}
+	rest := model[len(parts[0]):]
+	for i := 1; i < len(parts)-1; i++ {
+		idx := strings.Index(rest, parts[i])
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(parts[i]):]
+	}
+	return strings.HasSuffix(rest, parts[len(parts)-1])
+}
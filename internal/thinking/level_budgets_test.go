@@ -0,0 +1,61 @@
+package thinking_test
+
+import (
+	"testing"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/registry"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/thinking"
+	_ "github.com/router-for-me/CLIProxyAPI/v6/internal/thinking/provider/gemini"
+	"github.com/tidwall/gjson"
+)
+
+func TestConvertLevelToBudgetForModel_Overrides(t *testing.T) {
+	thinking.SetLevelBudgets(map[string]int{"low": 2048, "bogus": 1}, []thinking.LevelBudgetRule{
+		{Name: "Gemini-2.5-*", Budgets: map[string]int{"HIGH": 30000}},
+		{Name: "gemini-2.5-flash", Budgets: map[string]int{"high": 1}},
+	})
+	t.Cleanup(func() { thinking.SetLevelBudgets(nil, nil) })
+
+	cases := []struct {
+		model string
+		level string
+		want  int
+		ok    bool
+	}{
+		{model: "gemini-2.5-flash", level: "high", want: 30000, ok: true},
+		{model: "gemini-2.5-pro", level: "low", want: 2048, ok: true},
+		{model: "claude-sonnet-4", level: "high", want: 24576, ok: true},
+		{model: "", level: "medium", want: 8192, ok: true},
+		{model: "gemini-2.5-pro", level: "bogus", ok: false},
+	}
+	for _, tc := range cases {
+		got, ok := thinking.ConvertLevelToBudgetForModel(tc.model, tc.level)
+		if ok != tc.ok || got != tc.want {
+			t.Errorf("ConvertLevelToBudgetForModel(%q, %q) = %d, %v; want %d, %v", tc.model, tc.level, got, ok, tc.want, tc.ok)
+		}
+	}
+
+	thinking.SetLevelBudgets(nil, nil)
+	if got, _ := thinking.ConvertLevelToBudget("low"); got != 1024 {
+		t.Fatalf("defaults should be restored, low = %d", got)
+	}
+}
+
+func TestApplyThinking_UsesConfiguredLevelBudget(t *testing.T) {
+	reg := registry.GetGlobalRegistry()
+	clientID := "test-level-budgets-" + t.Name()
+	modelID := "level-budget-test-model"
+	reg.RegisterClient(clientID, "gemini", []*registry.ModelInfo{{ID: modelID, Thinking: &registry.ThinkingSupport{Min: 128, Max: 32768, ZeroAllowed: true, DynamicAllowed: true}}})
+	t.Cleanup(func() { reg.UnregisterClient(clientID) })
+
+	thinking.SetLevelBudgets(nil, []thinking.LevelBudgetRule{{Name: "level-budget-*", Budgets: map[string]int{"medium": 4000}}})
+	t.Cleanup(func() { thinking.SetLevelBudgets(nil, nil) })
+
+	out, err := thinking.ApplyThinking([]byte(`{}`), modelID+"(medium)", "openai", "gemini", "gemini")
+	if err != nil {
+		t.Fatalf("ApplyThinking() error = %v", err)
+	}
+	if got := gjson.GetBytes(out, "generationConfig.thinkingConfig.thinkingBudget").Int(); got != 4000 {
+		t.Fatalf("thinkingBudget = %d, want 4000; body = %s", got, out)
+	}
+}
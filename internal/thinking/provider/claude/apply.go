@@ -104,7 +104,7 @@ func (a *Applier) Apply(body []byte, config thinking.ThinkingConfig, modelInfo *
 		}
 
 		// Fallback for non-adaptive Claude models: convert level to budget_tokens.
-		if budget, ok := thinking.ConvertLevelToBudget(string(config.Level)); ok {
+		if budget, ok := thinking.ConvertLevelToBudgetForModel(modelInfo.ID, string(config.Level)); ok {
 			config.Mode = thinking.ModeBudget
 			config.Budget = budget
 			config.Level = ""
@@ -34,6 +34,9 @@ func ConvertAntigravityResponseToGemini(ctx context.Context, _ string, originalR
 	if bytes.HasPrefix(rawJSON, []byte("data:")) {
 		rawJSON = bytes.TrimSpace(rawJSON[5:])
 	}
+	if translatorcommon.ThoughtsSuppressed(ctx) {
+		rawJSON = translatorcommon.StripThoughtParts(rawJSON)
+	}
 
 	if alt, ok := ctx.Value("alt").(string); ok {
 		var chunk []byte
@@ -3,6 +3,9 @@ package gemini
 import (
 	"context"
 	"testing"
+
+	translatorcommon "github.com/router-for-me/CLIProxyAPI/v6/internal/translator/common"
+	"github.com/tidwall/gjson"
 )
 
 func TestRestoreUsageMetadata(t *testing.T) {
@@ -93,3 +96,23 @@ func TestConvertAntigravityResponseToGeminiStream(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertAntigravityResponseToGeminiSuppressesThoughts(t *testing.T) {
+	raw := []byte(`{"response":{"candidates":[{"content":{"role":"model","parts":[{"text":"pondering","thought":true},{"text":"answer"}]}}],"usageMetadata":{"thoughtsTokenCount":12}}}`)
+
+	ctx := context.WithValue(context.Background(), "alt", "")
+	out := ConvertAntigravityResponseToGemini(ctx, "", nil, nil, raw, nil)
+	if got := gjson.GetBytes(out[0], "candidates.0.content.parts.#").Int(); got != 2 {
+		t.Fatalf("expected thought part to be kept by default, got %d parts", got)
+	}
+
+	ctx = translatorcommon.WithSuppressedThoughts(ctx)
+	out = ConvertAntigravityResponseToGemini(ctx, "", nil, nil, raw, nil)
+	parts := gjson.GetBytes(out[0], "candidates.0.content.parts").Array()
+	if len(parts) != 1 || parts[0].Get("text").String() != "answer" {
+		t.Fatalf("expected only the answer part, got %s", out[0])
+	}
+	if got := gjson.GetBytes(out[0], "usageMetadata.thoughtsTokenCount").Int(); got != 12 {
+		t.Fatalf("expected thought tokens to be preserved, got %d", got)
+	}
+}
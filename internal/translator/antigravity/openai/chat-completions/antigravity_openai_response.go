@@ -13,6 +13,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	translatorcommon "github.com/router-for-me/CLIProxyAPI/v6/internal/translator/common"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/util"
 	log "github.com/sirupsen/logrus"
 
@@ -47,7 +48,7 @@ var functionCallIDCounter uint64
 //
 // Returns:
 //   - [][]byte: A slice of OpenAI-compatible JSON responses
-func ConvertAntigravityResponseToOpenAI(_ context.Context, _ string, originalRequestRawJSON, requestRawJSON, rawJSON []byte, param *any) [][]byte {
+func ConvertAntigravityResponseToOpenAI(ctx context.Context, _ string, originalRequestRawJSON, requestRawJSON, rawJSON []byte, param *any) [][]byte {
 	if *param == nil {
 		*param = &convertCliResponseToOpenAIChatParams{
 			UnixTimestamp:    0,
@@ -58,6 +59,9 @@ func ConvertAntigravityResponseToOpenAI(_ context.Context, _ string, originalReq
 	if (*param).(*convertCliResponseToOpenAIChatParams).SanitizedNameMap == nil {
 		(*param).(*convertCliResponseToOpenAIChatParams).SanitizedNameMap = util.SanitizedToolNameMap(originalRequestRawJSON)
 	}
+	if translatorcommon.ThoughtsSuppressed(ctx) {
+		rawJSON = translatorcommon.StripThoughtParts(rawJSON)
+	}
 
 	if bytes.Equal(rawJSON, []byte("[DONE]")) {
 		return [][]byte{}
@@ -3,6 +3,7 @@ package responses
 import (
 	"context"
 
+	translatorcommon "github.com/router-for-me/CLIProxyAPI/v6/internal/translator/common"
 	. "github.com/router-for-me/CLIProxyAPI/v6/internal/translator/gemini/openai/responses"
 	"github.com/tidwall/gjson"
 )
@@ -12,6 +13,9 @@ func ConvertAntigravityResponseToOpenAIResponses(ctx context.Context, modelName
 	if responseResult.Exists() {
 		rawJSON = []byte(responseResult.Raw)
 	}
+	if translatorcommon.ThoughtsSuppressed(ctx) {
+		rawJSON = translatorcommon.StripThoughtParts(rawJSON)
+	}
 	return ConvertGeminiResponseToOpenAIResponses(ctx, modelName, originalRequestRawJSON, requestRawJSON, rawJSON, param)
 }
 
@@ -170,7 +170,7 @@ func ConvertOpenAIRequestToClaude(modelName string, inputRawJSON []byte, stream
 			contentResult := message.Get("content")
 
 			switch role {
-			case "system":
+			case "system", "developer":
 				if contentResult.Exists() && contentResult.Type == gjson.String && contentResult.String() != "" {
 					textPart := []byte(`{"type":"text","text":""}`)
 					textPart, _ = sjson.SetBytes(textPart, "text", contentResult.String())
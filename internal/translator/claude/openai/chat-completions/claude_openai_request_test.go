@@ -243,3 +243,30 @@ func TestConvertOpenAIRequestToClaude_SystemOnlyInputKeepsFallbackUserMessage(t
 		t.Fatalf("Expected fallback text %q, got %q", "", got)
 	}
 }
+
+func TestConvertOpenAIRequestToClaude_DeveloperRoleBecomesSystem(t *testing.T) {
+	inputJSON := `{
+		"model": "gpt-4.1",
+		"messages": [
+			{"role": "developer", "content": "Answer tersely."},
+			{"role": "developer", "content": [{"type": "text", "text": "Use British spelling."}]},
+			{"role": "user", "content": "Hi"}
+		]
+	}`
+
+	result := ConvertOpenAIRequestToClaude("claude-sonnet-4-5", []byte(inputJSON), false)
+	system := gjson.GetBytes(result, "system").Array()
+	if len(system) < 2 {
+		t.Fatalf("expected developer messages in system, got %s", gjson.GetBytes(result, "system").Raw)
+	}
+	if got := system[len(system)-2].Get("text").String(); got != "Answer tersely." {
+		t.Fatalf("system text = %q", got)
+	}
+	if got := system[len(system)-1].Get("text").String(); got != "Use British spelling." {
+		t.Fatalf("system text = %q", got)
+	}
+	messages := gjson.GetBytes(result, "messages").Array()
+	if len(messages) != 1 || messages[0].Get("role").String() != "user" {
+		t.Fatalf("expected only the user message, got %s", gjson.GetBytes(result, "messages").Raw)
+	}
+}
@@ -139,7 +139,7 @@ func ConvertOpenAIResponsesRequestToClaude(modelName string, inputRawJSON []byte
 	if instructionsText == "" {
 		if input := root.Get("input"); input.Exists() && input.IsArray() {
 			input.ForEach(func(_, item gjson.Result) bool {
-				if strings.EqualFold(item.Get("role").String(), "system") {
+				if isSystemRole(item.Get("role").String()) {
 					var builder strings.Builder
 					if parts := item.Get("content"); parts.Exists() && parts.IsArray() {
 						parts.ForEach(func(_, part gjson.Result) bool {
@@ -170,7 +170,7 @@ func ConvertOpenAIResponsesRequestToClaude(modelName string, inputRawJSON []byte
 	// input array processing
 	if input := root.Get("input"); input.Exists() && input.IsArray() {
 		input.ForEach(func(_, item gjson.Result) bool {
-			if extractedFromSystem && strings.EqualFold(item.Get("role").String(), "system") {
+			if extractedFromSystem && isSystemRole(item.Get("role").String()) {
 				return true
 			}
 			typ := item.Get("type").String()
@@ -274,6 +274,8 @@ func ConvertOpenAIResponsesRequestToClaude(modelName string, inputRawJSON []byte
 					switch r {
 					case "user", "assistant", "system":
 						role = r
+					case "developer":
+						role = "system"
 					default:
 						role = "user"
 					}
@@ -391,3 +393,9 @@ func ConvertOpenAIResponsesRequestToClaude(modelName string, inputRawJSON []byte
 
 	return out
 }
+
+// isSystemRole reports whether an input role carries system instructions. OpenAI's
+// "developer" role supersedes "system" for newer models and is treated the same way.
+func isSystemRole(role string) bool {
+	return strings.EqualFold(role, "system") || strings.EqualFold(role, "developer")
+}
@@ -0,0 +1,69 @@
+package common
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+type suppressThoughtsKey struct{}
+
+// WithSuppressedThoughts marks ctx so response translators drop thought parts from the
+// output they produce.
+func WithSuppressedThoughts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, suppressThoughtsKey{}, true)
+}
+
+// ThoughtsSuppressed reports whether ctx was marked by WithSuppressedThoughts.
+func ThoughtsSuppressed(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	v, _ := ctx.Value(suppressThoughtsKey{}).(bool)
+	return v
+}
+
+// StripThoughtParts removes parts flagged as thoughts from every candidate of a Gemini
+// response chunk, with or without the Gemini CLI "response" envelope. Usage metadata is
+// left untouched so thinking tokens are still counted.
+func StripThoughtParts(rawJSON []byte) []byte {
+	prefix := "candidates"
+	candidates := gjson.GetBytes(rawJSON, prefix)
+	if !candidates.Exists() {
+		prefix = "response.candidates"
+		candidates = gjson.GetBytes(rawJSON, prefix)
+	}
+	if !candidates.IsArray() {
+		return rawJSON
+	}
+	out := rawJSON
+	for i, candidate := range candidates.Array() {
+		parts := candidate.Get("content.parts")
+		if !parts.IsArray() {
+			continue
+		}
+		kept := make([]byte, 0, len(parts.Raw))
+		kept = append(kept, '[')
+		removed := false
+		for _, part := range parts.Array() {
+			if part.Get("thought").Bool() {
+				removed = true
+				continue
+			}
+			if len(kept) > 1 {
+				kept = append(kept, ',')
+			}
+			kept = append(kept, part.Raw...)
+		}
+		if !removed {
+			continue
+		}
+		kept = append(kept, ']')
+		if updated, err := sjson.SetRawBytes(out, prefix+"."+strconv.Itoa(i)+".content.parts", kept); err == nil {
+			out = updated
+		}
+	}
+	return out
+}
@@ -85,3 +85,22 @@ func TestConvertOpenAIResponsesRequestToGemini_FailsClosedWhenFunctionNameCannot
 		t.Fatalf("expected fail-closed response without Gemini contents, got body=%s", string(out))
 	}
 }
+
+func TestConvertOpenAIResponsesRequestToGemini_DeveloperRoleBecomesSystemInstruction(t *testing.T) {
+	in := []byte(`{
+  "model":"gemini-2.5-pro",
+  "input":[
+    {"type":"message","role":"developer","content":[{"type":"input_text","text":"Answer tersely."}]},
+    {"type":"message","role":"user","content":[{"type":"input_text","text":"Hi"}]}
+  ]
+}`)
+
+	out := ConvertOpenAIResponsesRequestToGemini("gemini-2.5-pro", in, false)
+	if got := gjson.GetBytes(out, "systemInstruction.parts.0.text").String(); got != "Answer tersely." {
+		t.Fatalf("systemInstruction text = %q, output=%s", got, string(out))
+	}
+	contents := gjson.GetBytes(out, "contents").Array()
+	if len(contents) != 1 || contents[0].Get("role").String() != "user" {
+		t.Fatalf("expected only the user turn in contents, got %s", gjson.GetBytes(out, "contents").Raw)
+	}
+}
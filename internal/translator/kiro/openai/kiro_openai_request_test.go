@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/tidwall/gjson"
 )
 
 // TestToolResultsAttachedToCurrentMessage verifies that tool results from "tool" role messages
@@ -484,3 +486,14 @@ func TestAssistantEndsConversation(t *testing.T) {
 		t.Error("Expected a 'Continue' message to be created when assistant is last")
 	}
 }
+
+func TestDeveloperMessagesBecomeSystemPrompt(t *testing.T) {
+	messages := gjson.Parse(`[
+		{"role": "developer", "content": "Answer tersely."},
+		{"role": "system", "content": [{"type": "text", "text": "Be kind."}]},
+		{"role": "user", "content": "Hi"}
+	]`)
+	if got := extractSystemPromptFromOpenAI(messages); got != "Answer tersely.\nBe kind." {
+		t.Fatalf("system prompt = %q", got)
+	}
+}
@@ -0,0 +1,310 @@
+// Package translatordebug counts translator failures and optionally quarantines samples.
+//
+// Every failure reported by the translator registry is counted by (from, to, model). When
+// quarantine is enabled, a redacted copy of a sampled subset of the offending payloads is
+// written to a directory so it can be inspected through the management API.
+package translatordebug
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/util"
+	sdktranslator "github.com/router-for-me/CLIProxyAPI/v6/sdk/translator"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultMaxSamples is the number of quarantined samples kept when none is configured.
+	DefaultMaxSamples = 100
+	// DefaultMaxSampleBytes caps each stored payload when none is configured.
+	DefaultMaxSampleBytes = 64 * 1024
+
+	samplePrefix = "translator-"
+	sampleSuffix = ".json"
+)
+
+// ErrSampleNotFound is returned when a requested sample does not exist.
+var ErrSampleNotFound = errors.New("sample not found")
+
+// Settings configures quarantine of failed payloads.
+type Settings struct {
+	Quarantine     bool
+	Dir            string
+	SampleRate     float64
+	MaxSamples     int
+	MaxSampleBytes int
+}
+
+// Counter aggregates the failures of one (from, to, model) triple.
+type Counter struct {
+	From     string           `json:"from"`
+	To       string           `json:"to"`
+	Model    string           `json:"model"`
+	Total    int64            `json:"total"`
+	ByKind   map[string]int64 `json:"by_kind"`
+	ByStage  map[string]int64 `json:"by_stage"`
+	LastErr  string           `json:"last_error,omitempty"`
+	LastSeen time.Time        `json:"last_seen"`
+}
+
+// Sample is a quarantined failure as stored on disk.
+type Sample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Stage     string    `json:"stage"`
+	Kind      string    `json:"kind"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Model     string    `json:"model"`
+	Reason    string    `json:"reason"`
+	Input     string    `json:"input,omitempty"`
+	Output    string    `json:"output,omitempty"`
+	Truncated bool      `json:"truncated,omitempty"`
+}
+
+// SampleInfo lists a quarantined sample without its payload.
+type SampleInfo struct {
+	Name     string    `json:"name"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+type counterKey struct {
+	from, to, model string
+}
+
+var (
+	mu       sync.Mutex
+	counters = make(map[counterKey]*Counter)
+	settings Settings
+	seq      atomic.Uint64
+	hookOnce sync.Once
+)
+
+// Configure applies the quarantine settings and starts receiving translator failures.
+func Configure(s Settings) {
+	if s.SampleRate <= 0 || s.SampleRate > 1 {
+		s.SampleRate = 1
+	}
+	if s.MaxSamples <= 0 {
+		s.MaxSamples = DefaultMaxSamples
+	}
+	if s.MaxSampleBytes <= 0 {
+		s.MaxSampleBytes = DefaultMaxSampleBytes
+	}
+	s.Dir = strings.TrimSpace(s.Dir)
+	if s.Dir == "" {
+		s.Quarantine = false
+	}
+	mu.Lock()
+	settings = s
+	mu.Unlock()
+	hookOnce.Do(func() { sdktranslator.SetFailureHook(Record) })
+}
+
+// CurrentSettings returns the active quarantine settings.
+func CurrentSettings() Settings {
+	mu.Lock()
+	defer mu.Unlock()
+	return settings
+}
+
+// Record counts a translator failure and quarantines a sample when enabled.
+func Record(f sdktranslator.Failure) {
+	now := time.Now()
+	key := counterKey{from: f.From.String(), to: f.To.String(), model: f.Model}
+
+	mu.Lock()
+	c, ok := counters[key]
+	if !ok {
+		c = &Counter{From: key.from, To: key.to, Model: key.model, ByKind: map[string]int64{}, ByStage: map[string]int64{}}
+		counters[key] = c
+	}
+	c.Total++
+	c.ByKind[string(f.Kind)]++
+	c.ByStage[f.Stage]++
+	c.LastErr = f.Reason
+	c.LastSeen = now
+	s := settings
+	mu.Unlock()
+
+	log.Warnf("translator %s %s->%s failed for model %s: %s", f.Stage, f.From, f.To, f.Model, f.Kind)
+
+	if !s.Quarantine || (s.SampleRate < 1 && rand.Float64() >= s.SampleRate) {
+		return
+	}
+	if err := quarantine(s, f, now); err != nil {
+		log.Warnf("translator quarantine: %v", err)
+	}
+}
+
+// Counters returns the failure counters, most frequent first.
+func Counters() []Counter {
+	mu.Lock()
+	out := make([]Counter, 0, len(counters))
+	for _, c := range counters {
+		cp := *c
+		cp.ByKind = make(map[string]int64, len(c.ByKind))
+		for k, v := range c.ByKind {
+			cp.ByKind[k] = v
+		}
+		cp.ByStage = make(map[string]int64, len(c.ByStage))
+		for k, v := range c.ByStage {
+			cp.ByStage[k] = v
+		}
+		out = append(out, cp)
+	}
+	mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Total != out[j].Total {
+			return out[i].Total > out[j].Total
+		}
+		return out[i].LastSeen.After(out[j].LastSeen)
+	})
+	return out
+}
+
+// ResetCounters discards all failure counters.
+func ResetCounters() {
+	mu.Lock()
+	counters = make(map[counterKey]*Counter)
+	mu.Unlock()
+}
+
+// ListSamples returns the quarantined samples, newest first.
+func ListSamples() ([]SampleInfo, error) {
+	dir := CurrentSettings().Dir
+	if dir == "" {
+		return []SampleInfo{}, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []SampleInfo{}, nil
+		}
+		return nil, err
+	}
+	out := make([]SampleInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !isSampleName(entry.Name()) {
+			continue
+		}
+		info, errInfo := entry.Info()
+		if errInfo != nil {
+			continue
+		}
+		out = append(out, SampleInfo{Name: entry.Name(), Size: info.Size(), Modified: info.ModTime()})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name > out[j].Name })
+	return out, nil
+}
+
+// ReadSample loads a quarantined sample by file name.
+func ReadSample(name string) (*Sample, error) {
+	path, err := samplePath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrSampleNotFound
+		}
+		return nil, err
+	}
+	var sample Sample
+	if err = json.Unmarshal(data, &sample); err != nil {
+		return nil, fmt.Errorf("decode sample: %w", err)
+	}
+	return &sample, nil
+}
+
+// ClearSamples removes every quarantined sample and returns how many were deleted.
+func ClearSamples() (int, error) {
+	samples, err := ListSamples()
+	if err != nil {
+		return 0, err
+	}
+	dir := CurrentSettings().Dir
+	removed := 0
+	for _, sample := range samples {
+		if errRemove := os.Remove(filepath.Join(dir, sample.Name)); errRemove == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func samplePath(name string) (string, error) {
+	dir := CurrentSettings().Dir
+	if dir == "" || !isSampleName(name) || strings.ContainsAny(name, `/\`) {
+		return "", ErrSampleNotFound
+	}
+	return filepath.Join(dir, name), nil
+}
+
+func isSampleName(name string) bool {
+	return strings.HasPrefix(name, samplePrefix) && strings.HasSuffix(name, sampleSuffix)
+}
+
+func quarantine(s Settings, f sdktranslator.Failure, now time.Time) error {
+	sample := Sample{
+		Timestamp: now,
+		Stage:     f.Stage,
+		Kind:      string(f.Kind),
+		From:      f.From.String(),
+		To:        f.To.String(),
+		Model:     f.Model,
+		Reason:    f.Reason,
+	}
+	var truncIn, truncOut bool
+	sample.Input, truncIn = redactPayload(f.Input, s.MaxSampleBytes)
+	sample.Output, truncOut = redactPayload(f.Output, s.MaxSampleBytes)
+	sample.Truncated = truncIn || truncOut
+
+	data, err := json.MarshalIndent(sample, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode sample: %w", err)
+	}
+	if err = os.MkdirAll(s.Dir, 0o700); err != nil {
+		return fmt.Errorf("create quarantine dir: %w", err)
+	}
+	name := fmt.Sprintf("%s%s-%06d%s", samplePrefix, now.UTC().Format("20060102T150405.000Z"), seq.Add(1)%1000000, sampleSuffix)
+	if err = os.WriteFile(filepath.Join(s.Dir, name), data, 0o600); err != nil {
+		return fmt.Errorf("write sample: %w", err)
+	}
+	prune(s)
+	return nil
+}
+
+// redactPayload strips credentials from JSON payloads and caps the stored size.
+func redactPayload(payload []byte, limit int) (string, bool) {
+	if len(payload) == 0 {
+		return "", false
+	}
+	redacted := util.RedactSensitiveJSON(payload)
+	if len(redacted) > limit {
+		return string(redacted[:limit]), true
+	}
+	return string(redacted), false
+}
+
+// prune removes the oldest samples beyond the configured limit.
+func prune(s Settings) {
+	samples, err := ListSamples()
+	if err != nil || len(samples) <= s.MaxSamples {
+		return
+	}
+	for _, sample := range samples[s.MaxSamples:] {
+		_ = os.Remove(filepath.Join(s.Dir, sample.Name))
+	}
+}
@@ -0,0 +1,90 @@
+package translatordebug
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	sdktranslator "github.com/router-for-me/CLIProxyAPI/v6/sdk/translator"
+)
+
+func TestRegistryFailuresAreCountedAndQuarantined(t *testing.T) {
+	dir := t.TempDir()
+	Configure(Settings{Quarantine: true, Dir: dir, MaxSamples: 2})
+	t.Cleanup(func() {
+		Configure(Settings{})
+		ResetCounters()
+	})
+	ResetCounters()
+
+	reg := sdktranslator.NewRegistry()
+	from, to := sdktranslator.FromString("openai"), sdktranslator.FromString("claude")
+	reg.Register(from, to, func(model string, rawJSON []byte, stream bool) []byte {
+		return []byte(`{"broken":`)
+	}, sdktranslator.ResponseTransform{})
+
+	payload := []byte(`{"model":"m1","api_key":"sk-secret","messages":[]}`)
+	for i := 0; i < 3; i++ {
+		reg.TranslateRequest(from, to, "m1", payload, false)
+	}
+
+	counters := Counters()
+	if len(counters) != 1 {
+		t.Fatalf("expected one counter, got %+v", counters)
+	}
+	got := counters[0]
+	if got.From != "openai" || got.To != "claude" || got.Model != "m1" || got.Total != 3 {
+		t.Fatalf("unexpected counter: %+v", got)
+	}
+	if got.ByKind[string(sdktranslator.FailureInvalidJSON)] != 3 || got.ByStage[sdktranslator.StageRequest] != 3 {
+		t.Fatalf("unexpected breakdown: %+v", got)
+	}
+
+	samples, err := ListSamples()
+	if err != nil {
+		t.Fatalf("ListSamples: %v", err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("expected samples pruned to 2, got %d", len(samples))
+	}
+	sample, err := ReadSample(samples[0].Name)
+	if err != nil {
+		t.Fatalf("ReadSample: %v", err)
+	}
+	if strings.Contains(sample.Input, "sk-secret") {
+		t.Fatalf("sample input was not redacted: %s", sample.Input)
+	}
+	if sample.Output != `{"broken":` {
+		t.Fatalf("unexpected sample output: %q", sample.Output)
+	}
+
+	if _, err = ReadSample("../" + samples[0].Name); err != ErrSampleNotFound {
+		t.Fatalf("expected traversal to be rejected, got %v", err)
+	}
+}
+
+func TestPanicsAreCountedAndReraised(t *testing.T) {
+	Configure(Settings{})
+	t.Cleanup(ResetCounters)
+	ResetCounters()
+
+	reg := sdktranslator.NewRegistry()
+	from, to := sdktranslator.FromString("gemini"), sdktranslator.FromString("openai")
+	reg.Register(from, to, nil, sdktranslator.ResponseTransform{
+		NonStream: func(_ context.Context, _ string, _, _, _ []byte, _ *any) []byte { panic("boom") },
+	})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected panic to propagate")
+			}
+		}()
+		reg.TranslateNonStream(context.Background(), to, from, "m2", nil, nil, []byte(`{}`), nil)
+	}()
+
+	counters := Counters()
+	if len(counters) != 1 || counters[0].ByKind[string(sdktranslator.FailurePanic)] != 1 || counters[0].LastErr != "boom" {
+		t.Fatalf("unexpected counters: %+v", counters)
+	}
+}
@@ -0,0 +1,200 @@
+// Package warmup sends small generation requests ahead of real traffic.
+//
+// Warming each backing account for a set of models pre-establishes TLS connections and
+// provider-side caches, so the first client request after startup or a config reload does
+// not pay that latency. Results of the latest run are kept in memory for health reporting.
+package warmup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Target identifies a single warm-up request.
+type Target struct {
+	Model    string `json:"model"`
+	AuthID   string `json:"auth_id"`
+	Provider string `json:"provider"`
+}
+
+// ExecFunc performs the warm-up request for target.
+type ExecFunc func(ctx context.Context, target Target) error
+
+// Result records the outcome of warming one target.
+type Result struct {
+	Target
+	OK        bool      `json:"ok"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMs int64     `json:"latency_ms"`
+	At        time.Time `json:"at"`
+}
+
+// Status is a snapshot of the latest warm-up run.
+type Status struct {
+	Running    bool      `json:"running"`
+	StartedAt  time.Time `json:"started_at,omitempty"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+	Total      int       `json:"total"`
+	Succeeded  int       `json:"succeeded"`
+	Failed     int       `json:"failed"`
+	Results    []Result  `json:"results,omitempty"`
+}
+
+// Options tunes a warm-up run.
+type Options struct {
+	// Delay is waited before targets are resolved and the first request is sent.
+	Delay time.Duration
+	// Timeout bounds each request.
+	Timeout time.Duration
+	// Concurrency limits simultaneous requests.
+	Concurrency int
+}
+
+var (
+	mu     sync.Mutex
+	status Status
+	cancel context.CancelFunc
+	runID  uint64
+)
+
+// Start cancels any run in progress and warms targets in the background.
+// resolve is called after the delay so it sees accounts loaded in the meantime.
+func Start(resolve func() []Target, exec ExecFunc, opts Options) {
+	if resolve == nil || exec == nil {
+		return
+	}
+	ctx, cancelFn := context.WithCancel(context.Background())
+	mu.Lock()
+	if cancel != nil {
+		cancel()
+	}
+	cancel = cancelFn
+	runID++
+	id := runID
+	status = Status{Running: true}
+	mu.Unlock()
+
+	go func() {
+		defer cancelFn()
+		if opts.Delay > 0 {
+			timer := time.NewTimer(opts.Delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+		run(ctx, id, resolve(), exec, opts)
+	}()
+}
+
+// Stop cancels any run in progress.
+func Stop() {
+	mu.Lock()
+	if cancel != nil {
+		cancel()
+		cancel = nil
+	}
+	runID++
+	status.Running = false
+	mu.Unlock()
+}
+
+// CurrentStatus returns the latest run's state.
+func CurrentStatus() Status {
+	mu.Lock()
+	defer mu.Unlock()
+	out := status
+	out.Results = append([]Result(nil), status.Results...)
+	return out
+}
+
+func run(ctx context.Context, id uint64, targets []Target, exec ExecFunc, opts Options) {
+	started := time.Now()
+	if !update(id, func(s *Status) {
+		*s = Status{Running: len(targets) > 0, StartedAt: started, Total: len(targets)}
+		if len(targets) == 0 {
+			s.FinishedAt = started
+		}
+	}) || len(targets) == 0 {
+		return
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	log.Infof("warm-up: warming %d account/model pair(s)", len(targets))
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+			wg.Add(1)
+			go func(t Target) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				res := warmOne(ctx, t, exec, opts.Timeout)
+				if ctx.Err() != nil {
+					return
+				}
+				update(id, func(s *Status) {
+					s.Results = append(s.Results, res)
+					if res.OK {
+						s.Succeeded++
+					} else {
+						s.Failed++
+					}
+				})
+			}(target)
+			continue
+		}
+		break
+	}
+	wg.Wait()
+
+	update(id, func(s *Status) {
+		s.Running = false
+		s.FinishedAt = time.Now()
+		if ctx.Err() == nil {
+			log.Infof("warm-up: finished in %s (%d ok, %d failed)", s.FinishedAt.Sub(started).Round(time.Millisecond), s.Succeeded, s.Failed)
+		}
+	})
+}
+
+func warmOne(ctx context.Context, target Target, exec ExecFunc, timeout time.Duration) Result {
+	reqCtx := ctx
+	if timeout > 0 {
+		var cancelReq context.CancelFunc
+		reqCtx, cancelReq = context.WithTimeout(ctx, timeout)
+		defer cancelReq()
+	}
+	start := time.Now()
+	err := exec(reqCtx, target)
+	res := Result{Target: target, OK: err == nil, LatencyMs: time.Since(start).Milliseconds(), At: time.Now()}
+	if err != nil {
+		res.Error = err.Error()
+		if ctx.Err() == nil {
+			log.Warnf("warm-up: %s via %s (%s) failed after %dms: %v", target.Model, target.AuthID, target.Provider, res.LatencyMs, err)
+		}
+	} else {
+		log.Debugf("warm-up: %s via %s (%s) ok in %dms", target.Model, target.AuthID, target.Provider, res.LatencyMs)
+	}
+	return res
+}
+
+// update applies fn to the status when id is still the latest run.
+func update(id uint64, fn func(*Status)) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	if id != runID {
+		return false
+	}
+	fn(&status)
+	return true
+}
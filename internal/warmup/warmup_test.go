@@ -0,0 +1,72 @@
+package warmup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func waitFinished(t *testing.T) Status {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if st := CurrentStatus(); !st.StartedAt.IsZero() && !st.Running {
+			return st
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("warm-up did not finish")
+	return Status{}
+}
+
+func TestStartRecordsResults(t *testing.T) {
+	t.Cleanup(Stop)
+	targets := []Target{
+		{Model: "m1", AuthID: "a", Provider: "claude"},
+		{Model: "m1", AuthID: "b", Provider: "claude"},
+		{Model: "m2", AuthID: "c", Provider: "gemini"},
+	}
+	var calls atomic.Int32
+	Start(func() []Target { return targets }, func(ctx context.Context, target Target) error {
+		calls.Add(1)
+		if target.AuthID == "b" {
+			return errors.New("upstream unavailable")
+		}
+		return nil
+	}, Options{Concurrency: 2})
+
+	st := waitFinished(t)
+	if calls.Load() != 3 || st.Total != 3 || st.Succeeded != 2 || st.Failed != 1 || len(st.Results) != 3 {
+		t.Fatalf("unexpected status after %d calls: %+v", calls.Load(), st)
+	}
+	for _, r := range st.Results {
+		if r.AuthID == "b" && (r.OK || r.Error == "") {
+			t.Fatalf("failed target should record its error: %+v", r)
+		}
+	}
+}
+
+func TestStartCancelsPreviousRun(t *testing.T) {
+	t.Cleanup(Stop)
+	var stale atomic.Int32
+	Start(func() []Target {
+		return []Target{{Model: "old", AuthID: "a"}}
+	}, func(ctx context.Context, target Target) error {
+		stale.Add(1)
+		return nil
+	}, Options{Delay: time.Hour})
+
+	Start(func() []Target {
+		return []Target{{Model: "new", AuthID: "a"}}
+	}, func(ctx context.Context, target Target) error { return nil }, Options{})
+
+	st := waitFinished(t)
+	if stale.Load() != 0 {
+		t.Fatal("superseded run should not execute")
+	}
+	if len(st.Results) != 1 || st.Results[0].Model != "new" {
+		t.Fatalf("unexpected results: %+v", st.Results)
+	}
+}
@@ -24,6 +24,11 @@ type CodexModelsSummary struct {
 	count int
 }
 
+type GroqModelsSummary struct {
+	hash  string
+	count int
+}
+
 type VertexModelsSummary struct {
 	hash  string
 	count int
@@ -92,6 +97,27 @@ func SummarizeCodexModels(models []config.CodexModel) CodexModelsSummary {
 	}
 }
 
+// SummarizeGroqModels hashes Groq model aliases for change detection.
+func SummarizeGroqModels(models []config.GroqModel) GroqModelsSummary {
+	if len(models) == 0 {
+		return GroqModelsSummary{}
+	}
+	keys := normalizeModelPairs(func(out func(key string)) {
+		for _, model := range models {
+			name := strings.TrimSpace(model.Name)
+			alias := strings.TrimSpace(model.Alias)
+			if name == "" && alias == "" {
+				continue
+			}
+			out(strings.ToLower(name) + "|" + strings.ToLower(alias))
+		}
+	})
+	return GroqModelsSummary{
+		hash:  hashJoined(keys),
+		count: len(keys),
+	}
+}
+
 // SummarizeVertexModels hashes Vertex-compatible model aliases for change detection.
 func SummarizeVertexModels(models []config.VertexCompatModel) VertexModelsSummary {
 	if len(models) == 0 {
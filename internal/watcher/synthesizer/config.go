@@ -10,7 +10,7 @@ import (
 )
 
 // ConfigSynthesizer generates Auth entries from configuration API keys.
-// It handles Gemini, Claude, Codex, OpenAI-compat, and Vertex-compat providers.
+// It handles Gemini, Claude, Codex, Groq, OpenAI-compat, and Vertex-compat providers.
 type ConfigSynthesizer struct{}
 
 // NewConfigSynthesizer creates a new ConfigSynthesizer instance.
@@ -31,6 +31,8 @@ func (s *ConfigSynthesizer) Synthesize(ctx *SynthesisContext) ([]*coreauth.Auth,
 	out = append(out, s.synthesizeClaudeKeys(ctx)...)
 	// Codex API Keys
 	out = append(out, s.synthesizeCodexKeys(ctx)...)
+	// Groq API Keys
+	out = append(out, s.synthesizeGroqKeys(ctx)...)
 	// OpenAI-compat
 	out = append(out, s.synthesizeOpenAICompat(ctx)...)
 	// Vertex-compat
@@ -135,6 +137,54 @@ func (s *ConfigSynthesizer) synthesizeClaudeKeys(ctx *SynthesisContext) []*corea
 	return out
 }
 
+// synthesizeGroqKeys creates Auth entries for Groq API keys.
+func (s *ConfigSynthesizer) synthesizeGroqKeys(ctx *SynthesisContext) []*coreauth.Auth {
+	cfg := ctx.Config
+	now := ctx.Now
+	idGen := ctx.IDGenerator
+
+	out := make([]*coreauth.Auth, 0, len(cfg.GroqKey))
+	for i := range cfg.GroqKey {
+		gk := cfg.GroqKey[i]
+		key := strings.TrimSpace(gk.APIKey)
+		if key == "" {
+			continue
+		}
+		prefix := strings.TrimSpace(gk.Prefix)
+		base := strings.TrimSpace(gk.BaseURL)
+		id, token := idGen.Next("groq:apikey", key, base)
+		attrs := map[string]string{
+			"source":  fmt.Sprintf("config:groq[%s]", token),
+			"api_key": key,
+		}
+		if gk.Priority != 0 {
+			attrs["priority"] = strconv.Itoa(gk.Priority)
+		}
+		if base != "" {
+			attrs["base_url"] = base
+		}
+		if hash := diff.ComputeGroqModelsHash(gk.Models); hash != "" {
+			attrs["models_hash"] = hash
+		}
+		addConfigHeadersToAttrs(gk.Headers, attrs)
+		proxyURL := strings.TrimSpace(gk.ProxyURL)
+		a := &coreauth.Auth{
+			ID:         id,
+			Provider:   "groq",
+			Label:      "groq-apikey",
+			Prefix:     prefix,
+			Status:     coreauth.StatusActive,
+			ProxyURL:   proxyURL,
+			Attributes: attrs,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		ApplyAuthExcludedModelsMeta(a, cfg, gk.ExcludedModels, "apikey")
+		out = append(out, a)
+	}
+	return out
+}
+
 // synthesizeCodexKeys creates Auth entries for Codex API keys.
 func (s *ConfigSynthesizer) synthesizeCodexKeys(ctx *SynthesisContext) []*coreauth.Auth {
 	cfg := ctx.Config
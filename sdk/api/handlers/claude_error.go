@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/interfaces"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/auth"
+	"github.com/tidwall/gjson"
+)
+
+// StatusClaudeOverloaded is the non-standard HTTP status Anthropic uses for overloaded_error.
+const StatusClaudeOverloaded = 529
+
+type claudeErrorDetail struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type claudeErrorResponse struct {
+	Type  string            `json:"type"`
+	Error claudeErrorDetail `json:"error"`
+}
+
+// BuildClaudeErrorBody renders msg as an Anthropic error payload. Cooldown refusals map to
+// overloaded_error so Claude clients apply their overload retry policy; everything else
+// is reported as api_error.
+func BuildClaudeErrorBody(msg *interfaces.ErrorMessage) []byte {
+	resp := claudeErrorResponse{Type: "error", Error: claudeErrorDetail{Type: "api_error"}}
+	if msg != nil && msg.Error != nil {
+		resp.Error.Message = msg.Error.Error()
+		if _, ok := coreauth.CooldownRetryAfter(msg.Error); ok {
+			resp.Error.Type = "overloaded_error"
+			if m := gjson.Get(resp.Error.Message, "error.message").String(); m != "" {
+				resp.Error.Message = m
+			}
+		}
+	}
+	if resp.Error.Message == "" {
+		resp.Error.Message = http.StatusText(http.StatusInternalServerError)
+	}
+	data, _ := json.Marshal(resp)
+	return data
+}
+
+// WriteClaudeErrorResponse writes msg for Anthropic-compatible clients. Cooldown refusals are
+// sent as overloaded_error with status 529 and Retry-After; other errors go through
+// WriteErrorResponse so upstream payloads keep passing through unchanged.
+func (h *BaseAPIHandler) WriteClaudeErrorResponse(c *gin.Context, msg *interfaces.ErrorMessage) {
+	if msg == nil || !WriteCooldownRetryAfter(c.Writer.Header(), msg.Error) {
+		h.WriteErrorResponse(c, msg)
+		return
+	}
+	writeErrorBody(c, StatusClaudeOverloaded, msg.Error.Error(), BuildClaudeErrorBody(msg))
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/interfaces"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/auth"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/executor"
+	"github.com/tidwall/gjson"
+)
+
+func cooldownErrorMessage(t *testing.T) *interfaces.ErrorMessage {
+	t.Helper()
+	model := "claude-sonnet-4-6"
+	next := time.Now().Add(30 * time.Second)
+	auths := []*coreauth.Auth{{
+		ID:       "cooling",
+		Provider: "claude",
+		ModelStates: map[string]*coreauth.ModelState{
+			model: {
+				Status:         coreauth.StatusActive,
+				Unavailable:    true,
+				NextRetryAfter: next,
+				Quota:          coreauth.QuotaState{Exceeded: true, NextRecoverAt: next},
+			},
+		},
+	}}
+	_, err := (&coreauth.FillFirstSelector{}).Pick(context.Background(), "claude", model, cliproxyexecutor.Options{}, auths)
+	if _, ok := coreauth.CooldownRetryAfter(err); !ok {
+		t.Fatalf("expected cooldown error, got %v", err)
+	}
+	return &interfaces.ErrorMessage{StatusCode: http.StatusTooManyRequests, Error: err}
+}
+
+func TestWriteClaudeErrorResponse_CooldownIsOverloaded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", nil)
+
+	NewBaseAPIHandlers(nil, nil).WriteClaudeErrorResponse(c, cooldownErrorMessage(t))
+
+	if recorder.Code != StatusClaudeOverloaded {
+		t.Fatalf("status = %d, want %d", recorder.Code, StatusClaudeOverloaded)
+	}
+	if recorder.Header().Get("Retry-After") == "" {
+		t.Fatal("Retry-After should be set for cooldown errors")
+	}
+	body := recorder.Body.Bytes()
+	if got := gjson.GetBytes(body, "type").String(); got != "error" {
+		t.Fatalf("type = %q, want error; body = %s", got, body)
+	}
+	if got := gjson.GetBytes(body, "error.type").String(); got != "overloaded_error" {
+		t.Fatalf("error.type = %q, want overloaded_error; body = %s", got, body)
+	}
+	if msg := gjson.GetBytes(body, "error.message").String(); msg == "" || gjson.Valid(msg) {
+		t.Fatalf("error.message should be plain text, got %q", msg)
+	}
+}
+
+func TestWriteErrorResponse_CooldownIsInsufficientQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+
+	NewBaseAPIHandlers(nil, nil).WriteErrorResponse(c, cooldownErrorMessage(t))
+
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusTooManyRequests)
+	}
+	if recorder.Header().Get("Retry-After") == "" {
+		t.Fatal("Retry-After should be set for cooldown errors even without passthrough")
+	}
+	body := recorder.Body.Bytes()
+	if got := gjson.GetBytes(body, "error.type").String(); got != "insufficient_quota" {
+		t.Fatalf("error.type = %q, want insufficient_quota; body = %s", got, body)
+	}
+	if got := gjson.GetBytes(body, "error.code").String(); got != coreauth.ErrorCodeModelCooldown {
+		t.Fatalf("error.code = %q, want %q", got, coreauth.ErrorCodeModelCooldown)
+	}
+}
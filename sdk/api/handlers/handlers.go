@@ -8,13 +8,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/inflight"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/interfaces"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/logging"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/thinking"
@@ -151,6 +154,45 @@ func BuildErrorResponseBody(status int, errText string) []byte {
 	return payload
 }
 
+// buildOfflineErrorResponseBody renders offline-mode refusals with a distinct error code
+// so clients can distinguish them from upstream outages. Returns nil for other errors.
+func buildOfflineErrorResponseBody(msg *interfaces.ErrorMessage) []byte {
+	if msg == nil || msg.Error == nil {
+		return nil
+	}
+	var authErr *coreauth.Error
+	if !errors.As(msg.Error, &authErr) || authErr == nil || authErr.Code != coreauth.ErrorCodeOffline {
+		return nil
+	}
+	payload, err := json.Marshal(ErrorResponse{
+		Error: ErrorDetail{
+			Message: authErr.Message,
+			Type:    "server_error",
+			Code:    coreauth.ErrorCodeOffline,
+		},
+	})
+	if err != nil {
+		return nil
+	}
+	return payload
+}
+
+// WriteCooldownRetryAfter sets Retry-After when err reports that every credential is cooling
+// down, so client retry logic waits for the earliest reset even without header passthrough.
+// It reports whether err was a cooldown error.
+func WriteCooldownRetryAfter(dst http.Header, err error) bool {
+	resetIn, ok := coreauth.CooldownRetryAfter(err)
+	if !ok || dst == nil {
+		return ok
+	}
+	seconds := int(math.Ceil(resetIn.Seconds()))
+	if seconds < 0 {
+		seconds = 0
+	}
+	dst.Set("Retry-After", strconv.Itoa(seconds))
+	return true
+}
+
 // StreamingKeepAliveInterval returns the SSE keep-alive interval for this server.
 // Returning 0 disables keep-alives (default when unset).
 func StreamingKeepAliveInterval(cfg *config.SDKConfig) time.Duration {
@@ -190,9 +232,19 @@ func StreamingBootstrapRetries(cfg *config.SDKConfig) int {
 }
 
 // PassthroughHeadersEnabled returns whether upstream response headers should be forwarded to clients.
+// Forwarding is enabled by passthrough-headers or a non-empty passthrough-headers-allowlist.
 // Default is false.
 func PassthroughHeadersEnabled(cfg *config.SDKConfig) bool {
-	return cfg != nil && cfg.PassthroughHeaders
+	return cfg != nil && (cfg.PassthroughHeaders || len(cfg.PassthroughHeadersAllowlist) > 0)
+}
+
+// passthroughHeaders filters upstream headers for forwarding using the configured allowlist.
+func (h *BaseAPIHandler) passthroughHeaders(src http.Header) http.Header {
+	var allowlist []string
+	if h.Cfg != nil {
+		allowlist = h.Cfg.PassthroughHeadersAllowlist
+	}
+	return FilterPassthroughHeaders(src, allowlist)
 }
 
 func requestExecutionMetadata(ctx context.Context) map[string]any {
@@ -219,7 +271,18 @@ func requestExecutionMetadata(ctx context.Context) map[string]any {
 	if pinnedAuthID := pinnedAuthIDFromContext(ctx); pinnedAuthID != "" {
 		meta[coreexecutor.PinnedAuthMetadataKey] = pinnedAuthID
 	}
-	if selectedCallback := selectedAuthIDCallbackFromContext(ctx); selectedCallback != nil {
+	selectedCallback := selectedAuthIDCallbackFromContext(ctx)
+	if entry := inflight.FromContext(ctx); entry != nil {
+		// Record the serving account for the in-flight inspector while preserving any caller callback.
+		clientCallback := selectedCallback
+		selectedCallback = func(authID string) {
+			entry.SetAuth(authID)
+			if clientCallback != nil {
+				clientCallback(authID)
+			}
+		}
+	}
+	if selectedCallback != nil {
 		meta[coreexecutor.SelectedAuthCallbackMetadataKey] = selectedCallback
 	}
 	if executionSessionID := executionSessionIDFromContext(ctx); executionSessionID != "" {
@@ -390,6 +453,16 @@ func (h *BaseAPIHandler) GetContextWithCancel(handler interfaces.APIHandler, c *
 			}
 		}()
 	}
+	var method, path string
+	if requestCtx != nil {
+		method, path = c.Request.Method, c.Request.URL.Path
+	}
+	entry := inflight.Register(logging.GetRequestID(parentCtx), method, path, cancel)
+	go func() {
+		<-cancelCtx.Done()
+		entry.Done()
+	}()
+	newCtx = inflight.WithEntry(newCtx, entry)
 	newCtx = context.WithValue(newCtx, "gin", c)
 	newCtx = context.WithValue(newCtx, "handler", handler)
 	return newCtx, func(params ...interface{}) {
@@ -514,6 +587,7 @@ func (h *BaseAPIHandler) ExecuteWithAuthManager(ctx context.Context, handlerType
 	if errMsg != nil {
 		return nil, nil, errMsg
 	}
+	inflight.FromContext(ctx).SetModel(normalizedModel, providers, false)
 	reqMeta := requestExecutionMetadata(ctx)
 	reqMeta[coreexecutor.RequestedModelMetadataKey] = normalizedModel
 	payload := rawJSON
@@ -552,7 +626,7 @@ func (h *BaseAPIHandler) ExecuteWithAuthManager(ctx context.Context, handlerType
 	if !PassthroughHeadersEnabled(h.Cfg) {
 		return resp.Payload, nil, nil
 	}
-	return resp.Payload, FilterUpstreamHeaders(resp.Headers), nil
+	return resp.Payload, h.passthroughHeaders(resp.Headers), nil
 }
 
 // ExecuteCountWithAuthManager executes a non-streaming request via the core auth manager.
@@ -562,6 +636,7 @@ func (h *BaseAPIHandler) ExecuteCountWithAuthManager(ctx context.Context, handle
 	if errMsg != nil {
 		return nil, nil, errMsg
 	}
+	inflight.FromContext(ctx).SetModel(normalizedModel, providers, false)
 	reqMeta := requestExecutionMetadata(ctx)
 	reqMeta[coreexecutor.RequestedModelMetadataKey] = normalizedModel
 	payload := rawJSON
@@ -600,7 +675,7 @@ func (h *BaseAPIHandler) ExecuteCountWithAuthManager(ctx context.Context, handle
 	if !PassthroughHeadersEnabled(h.Cfg) {
 		return resp.Payload, nil, nil
 	}
-	return resp.Payload, FilterUpstreamHeaders(resp.Headers), nil
+	return resp.Payload, h.passthroughHeaders(resp.Headers), nil
 }
 
 // ExecuteStreamWithAuthManager executes a streaming request via the core auth manager.
@@ -614,6 +689,7 @@ func (h *BaseAPIHandler) ExecuteStreamWithAuthManager(ctx context.Context, handl
 		close(errChan)
 		return nil, nil, errChan
 	}
+	inflight.FromContext(ctx).SetModel(normalizedModel, providers, true)
 	reqMeta := requestExecutionMetadata(ctx)
 	reqMeta[coreexecutor.RequestedModelMetadataKey] = normalizedModel
 	payload := rawJSON
@@ -657,12 +733,13 @@ func (h *BaseAPIHandler) ExecuteStreamWithAuthManager(ctx context.Context, handl
 	// Keep a mutable map so bootstrap retries can replace it before first payload is sent.
 	var upstreamHeaders http.Header
 	if passthroughHeadersEnabled {
-		upstreamHeaders = cloneHeader(FilterUpstreamHeaders(streamResult.Headers))
+		upstreamHeaders = cloneHeader(h.passthroughHeaders(streamResult.Headers))
 		if upstreamHeaders == nil {
 			upstreamHeaders = make(http.Header)
 		}
 	}
 	chunks := streamResult.Chunks
+	inflightEntry := inflight.FromContext(ctx)
 	dataChan := make(chan []byte)
 	errChan := make(chan *interfaces.ErrorMessage, 1)
 	go func() {
@@ -739,7 +816,7 @@ func (h *BaseAPIHandler) ExecuteStreamWithAuthManager(ctx context.Context, handl
 							retryResult, retryErr := h.AuthManager.ExecuteStream(ctx, providers, req, opts)
 							if retryErr == nil {
 								if passthroughHeadersEnabled {
-									replaceHeader(upstreamHeaders, FilterUpstreamHeaders(retryResult.Headers))
+									replaceHeader(upstreamHeaders, h.passthroughHeaders(retryResult.Headers))
 								}
 								chunks = retryResult.Chunks
 								continue outer
@@ -774,6 +851,7 @@ func (h *BaseAPIHandler) ExecuteStreamWithAuthManager(ctx context.Context, handl
 					if okSendData := sendData(cloneBytes(chunk.Payload)); !okSendData {
 						return
 					}
+					inflightEntry.AddBytes(len(chunk.Payload))
 				}
 			}
 		}
@@ -949,7 +1027,7 @@ func (h *BaseAPIHandler) WriteErrorResponse(c *gin.Context, msg *interfaces.Erro
 		status = msg.StatusCode
 	}
 	if msg != nil && msg.Addon != nil && PassthroughHeadersEnabled(h.Cfg) {
-		for key, values := range msg.Addon {
+		for key, values := range h.passthroughHeaders(msg.Addon) {
 			if len(values) == 0 {
 				continue
 			}
@@ -960,6 +1038,10 @@ func (h *BaseAPIHandler) WriteErrorResponse(c *gin.Context, msg *interfaces.Erro
 		}
 	}
 
+	if msg != nil {
+		WriteCooldownRetryAfter(c.Writer.Header(), msg.Error)
+	}
+
 	errText := http.StatusText(status)
 	if msg != nil && msg.Error != nil {
 		if v := strings.TrimSpace(msg.Error.Error()); v != "" {
@@ -968,6 +1050,14 @@ func (h *BaseAPIHandler) WriteErrorResponse(c *gin.Context, msg *interfaces.Erro
 	}
 
 	body := BuildErrorResponseBody(status, errText)
+	if offlineBody := buildOfflineErrorResponseBody(msg); offlineBody != nil {
+		body = offlineBody
+	}
+	writeErrorBody(c, status, errText, body)
+}
+
+// writeErrorBody records body in the request log and writes it with the given status.
+func writeErrorBody(c *gin.Context, status int, errText string, body []byte) {
 	// Append first to preserve upstream response logs, then drop duplicate payloads if already recorded.
 	var previous []byte
 	if existing, exists := c.Get("API_RESPONSE"); exists {
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/inflight"
+	coreexecutor "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/executor"
+	sdkconfig "github.com/router-for-me/CLIProxyAPI/v6/sdk/config"
+)
+
+func TestGetContextWithCancelTracksInflightRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+
+	h := &BaseAPIHandler{Cfg: &sdkconfig.SDKConfig{}}
+	ctx, cancel := h.GetContextWithCancel(nil, c, context.Background())
+	entry := inflight.FromContext(ctx)
+	if entry == nil {
+		t.Fatal("expected in-flight entry in context")
+	}
+
+	meta := requestExecutionMetadata(ctx)
+	callback, ok := meta[coreexecutor.SelectedAuthCallbackMetadataKey].(func(string))
+	if !ok {
+		t.Fatal("expected selected auth callback in metadata")
+	}
+	callback("auth-42")
+
+	var listed bool
+	for _, r := range inflight.List() {
+		if r.ID == entry.ID() {
+			listed = true
+			if r.AuthID != "auth-42" || r.Path != "/v1/chat/completions" {
+				t.Fatalf("unexpected snapshot: %+v", r)
+			}
+		}
+	}
+	if !listed {
+		t.Fatal("request not listed while in flight")
+	}
+
+	if !inflight.Cancel(entry.ID()) {
+		t.Fatal("Cancel returned false")
+	}
+	if ctx.Err() == nil {
+		t.Fatal("request context not cancelled")
+	}
+	cancel()
+}
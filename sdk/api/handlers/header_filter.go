@@ -73,6 +73,46 @@ func FilterUpstreamHeaders(src http.Header) http.Header {
 	return dst
 }
 
+// FilterPassthroughHeaders applies FilterUpstreamHeaders and, when allowlist is non-empty,
+// keeps only headers whose names match an entry. Matching is case-insensitive and an entry
+// ending in "*" matches any header starting with the text before it.
+// Returns nil if nothing remains.
+func FilterPassthroughHeaders(src http.Header, allowlist []string) http.Header {
+	filtered := FilterUpstreamHeaders(src)
+	if filtered == nil || len(allowlist) == 0 {
+		return filtered
+	}
+	for key := range filtered {
+		if !headerAllowed(key, allowlist) {
+			delete(filtered, key)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+func headerAllowed(key string, allowlist []string) bool {
+	lowerKey := strings.ToLower(key)
+	for _, entry := range allowlist {
+		pattern := strings.ToLower(strings.TrimSpace(entry))
+		if pattern == "" {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(lowerKey, prefix) {
+				return true
+			}
+			continue
+		}
+		if lowerKey == pattern {
+			return true
+		}
+	}
+	return false
+}
+
 func connectionScopedHeaders(src http.Header) map[string]struct{} {
 	scoped := make(map[string]struct{})
 	for _, rawValue := range src.Values("Connection") {
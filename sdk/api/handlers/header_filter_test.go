@@ -53,3 +53,31 @@ func TestFilterUpstreamHeaders_ReturnsNilWhenAllHeadersBlocked(t *testing.T) {
 		t.Fatalf("expected nil when all headers are filtered, got %#v", filtered)
 	}
 }
+
+func TestFilterPassthroughHeaders_AppliesAllowlist(t *testing.T) {
+	src := http.Header{}
+	src.Set("Anthropic-Ratelimit-Requests-Remaining", "42")
+	src.Set("X-Ratelimit-Reset-Tokens", "6s")
+	src.Set("Retry-After", "3")
+	src.Set("X-Request-Id", "req_1")
+	src.Set("Set-Cookie", "session=secret")
+
+	filtered := FilterPassthroughHeaders(src, []string{"anthropic-ratelimit-*", "X-RateLimit-*", "retry-after", "Set-Cookie"})
+	for _, key := range []string{"Anthropic-Ratelimit-Requests-Remaining", "X-Ratelimit-Reset-Tokens", "Retry-After"} {
+		if filtered.Get(key) == "" {
+			t.Fatalf("expected %s to be forwarded, got %#v", key, filtered)
+		}
+	}
+	for _, key := range []string{"X-Request-Id", "Set-Cookie"} {
+		if value := filtered.Get(key); value != "" {
+			t.Fatalf("expected %s to be removed, got %q", key, value)
+		}
+	}
+
+	if all := FilterPassthroughHeaders(src, nil); all.Get("X-Request-Id") == "" {
+		t.Fatalf("expected empty allowlist to forward all filtered headers, got %#v", all)
+	}
+	if none := FilterPassthroughHeaders(src, []string{"x-unrelated"}); none != nil {
+		t.Fatalf("expected nil when no header matches, got %#v", none)
+	}
+}
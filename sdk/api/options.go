@@ -5,6 +5,7 @@
 package api
 
 import (
+	"net"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -44,3 +45,12 @@ func WithKeepAliveEndpoint(timeout time.Duration, onTimeout func()) ServerOption
 func WithRequestLoggerFactory(factory func(*config.Config, string) logging.RequestLogger) ServerOption {
 	return internalapi.WithRequestLoggerFactory(factory)
 }
+
+// WithListener serves on a pre-built listener instead of the configured host and port.
+func WithListener(ln net.Listener) ServerOption { return internalapi.WithListener(ln) }
+
+// WithoutDefaultMiddleware skips the built-in Gin logging and recovery middleware.
+func WithoutDefaultMiddleware() ServerOption { return internalapi.WithoutDefaultMiddleware() }
+
+// WithBasePath mounts every route under prefix; requests outside it receive 404.
+func WithBasePath(prefix string) ServerOption { return internalapi.WithBasePath(prefix) }
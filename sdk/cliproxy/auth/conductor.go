@@ -18,6 +18,8 @@ import (
 	"github.com/google/uuid"
 	internalconfig "github.com/router-for-me/CLIProxyAPI/v6/internal/config"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/logging"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/maintenance"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/offline"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/registry"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/thinking"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/util"
@@ -965,6 +967,10 @@ func (m *Manager) rebuildAPIKeyModelAliasLocked(cfg *internalconfig.Config) {
 			if entry := resolveCodexAPIKeyConfig(cfg, auth); entry != nil {
 				compileAPIKeyModelAliasForModels(byAlias, entry.Models)
 			}
+		case "groq":
+			if entry := resolveGroqAPIKeyConfig(cfg, auth); entry != nil {
+				compileAPIKeyModelAliasForModels(byAlias, entry.Models)
+			}
 		case "vertex":
 			if entry := resolveVertexAPIKeyConfig(cfg, auth); entry != nil {
 				compileAPIKeyModelAliasForModels(byAlias, entry.Models)
@@ -1303,7 +1309,7 @@ func (m *Manager) executeMixedOnce(ctx context.Context, providers []string, req
 			if lastErr != nil {
 				return cliproxyexecutor.Response{}, lastErr
 			}
-			return cliproxyexecutor.Response{}, errPick
+			return cliproxyexecutor.Response{}, offlinePickError(errPick)
 		}
 
 		entry := logEntryWithRequestID(ctx)
@@ -1381,7 +1387,7 @@ func (m *Manager) executeCountMixedOnce(ctx context.Context, providers []string,
 			if lastErr != nil {
 				return cliproxyexecutor.Response{}, lastErr
 			}
-			return cliproxyexecutor.Response{}, errPick
+			return cliproxyexecutor.Response{}, offlinePickError(errPick)
 		}
 
 		entry := logEntryWithRequestID(ctx)
@@ -1459,7 +1465,7 @@ func (m *Manager) executeStreamMixedOnce(ctx context.Context, providers []string
 			if lastErr != nil {
 				return nil, lastErr
 			}
-			return nil, errPick
+			return nil, offlinePickError(errPick)
 		}
 
 		entry := logEntryWithRequestID(ctx)
@@ -1646,6 +1652,8 @@ func (m *Manager) applyAPIKeyModelAlias(auth *Auth, requestedModel string) strin
 		upstreamModel = resolveUpstreamModelForClaudeAPIKey(cfg, auth, requestedModel)
 	case "codex":
 		upstreamModel = resolveUpstreamModelForCodexAPIKey(cfg, auth, requestedModel)
+	case "groq":
+		upstreamModel = resolveUpstreamModelForGroqAPIKey(cfg, auth, requestedModel)
 	case "vertex":
 		upstreamModel = resolveUpstreamModelForVertexAPIKey(cfg, auth, requestedModel)
 	default:
@@ -1725,6 +1733,13 @@ func resolveCodexAPIKeyConfig(cfg *internalconfig.Config, auth *Auth) *internalc
 	return resolveAPIKeyConfig(cfg.CodexKey, auth)
 }
 
+func resolveGroqAPIKeyConfig(cfg *internalconfig.Config, auth *Auth) *internalconfig.GroqKey {
+	if cfg == nil {
+		return nil
+	}
+	return resolveAPIKeyConfig(cfg.GroqKey, auth)
+}
+
 func resolveVertexAPIKeyConfig(cfg *internalconfig.Config, auth *Auth) *internalconfig.VertexCompatKey {
 	if cfg == nil {
 		return nil
@@ -1748,6 +1763,14 @@ func resolveUpstreamModelForClaudeAPIKey(cfg *internalconfig.Config, auth *Auth,
 	return resolveModelAliasFromConfigModels(requestedModel, asModelAliasEntries(entry.Models))
 }
 
+func resolveUpstreamModelForGroqAPIKey(cfg *internalconfig.Config, auth *Auth, requestedModel string) string {
+	entry := resolveGroqAPIKeyConfig(cfg, auth)
+	if entry == nil {
+		return ""
+	}
+	return resolveModelAliasFromConfigModels(requestedModel, asModelAliasEntries(entry.Models))
+}
+
 func resolveUpstreamModelForCodexAPIKey(cfg *internalconfig.Config, auth *Auth, requestedModel string) string {
 	entry := resolveCodexAPIKeyConfig(cfg, auth)
 	if entry == nil {
@@ -1968,7 +1991,7 @@ func (m *Manager) shouldRetryAfterError(err error, attempt int, providers []stri
 	if status == http.StatusOK {
 		return 0, false
 	}
-	if isRequestInvalidError(err) {
+	if isRequestInvalidError(err) || isOfflineError(err) {
 		return 0, false
 	}
 	wait, found := m.closestCooldownWait(providers, model, attempt)
@@ -2659,6 +2682,50 @@ func shouldRetrySchedulerPick(err error) bool {
 	return authErr.Code == "auth_not_found" || authErr.Code == "auth_unavailable"
 }
 
+// offlineBlocksAuth reports whether offline mode forbids executing requests with auth.
+func offlineBlocksAuth(auth *Auth) bool {
+	if auth == nil || !offline.Active() {
+		return false
+	}
+	baseURL := ""
+	if auth.Attributes != nil {
+		baseURL = auth.Attributes["base_url"]
+	}
+	return !offline.IsLocal(auth.Provider, baseURL)
+}
+
+// maintenanceBlocksAuth reports whether a configured maintenance window currently
+// excludes auth from routing.
+func maintenanceBlocksAuth(auth *Auth, now time.Time) bool {
+	if auth == nil {
+		return false
+	}
+	email := ""
+	if auth.Metadata != nil {
+		email, _ = auth.Metadata["email"].(string)
+	}
+	blocked, _ := maintenance.Blocked([]string{auth.ID, auth.FileName, auth.Label, email}, now)
+	return blocked
+}
+
+// offlinePickError replaces "no auth available" selection failures with a distinct
+// offline error while offline mode is active, so clients can tell the two apart.
+func offlinePickError(err error) error {
+	if err == nil || !offline.Active() || !shouldRetrySchedulerPick(err) {
+		return err
+	}
+	return &Error{
+		Code:       ErrorCodeOffline,
+		Message:    "offline mode is active; upstream provider calls are disabled",
+		HTTPStatus: http.StatusServiceUnavailable,
+	}
+}
+
+func isOfflineError(err error) bool {
+	var authErr *Error
+	return errors.As(err, &authErr) && authErr != nil && authErr.Code == ErrorCodeOffline
+}
+
 func (m *Manager) routeAwareSelectionRequired(auth *Auth, routeModel string) bool {
 	if auth == nil || strings.TrimSpace(routeModel) == "" {
 		return false
@@ -2686,6 +2753,7 @@ func (m *Manager) pickNextLegacy(ctx context.Context, provider, model string, op
 		}
 	}
 	registryRef := registry.GetGlobalRegistry()
+	now := time.Now()
 	for _, candidate := range m.auths {
 		if candidate.Provider != provider || candidate.Disabled {
 			continue
@@ -2696,6 +2764,9 @@ func (m *Manager) pickNextLegacy(ctx context.Context, provider, model string, op
 		if disallowFreeAuth && isFreeCodexAuth(candidate) {
 			continue
 		}
+		if maintenanceBlocksAuth(candidate, now) {
+			continue
+		}
 		if _, used := tried[candidate.ID]; used {
 			continue
 		}
@@ -2708,7 +2779,7 @@ func (m *Manager) pickNextLegacy(ctx context.Context, provider, model string, op
 		m.mu.RUnlock()
 		return nil, nil, &Error{Code: "auth_not_found", Message: "no auth available"}
 	}
-	available, errAvailable := m.availableAuthsForRouteModel(candidates, provider, model, time.Now())
+	available, errAvailable := m.availableAuthsForRouteModel(candidates, provider, model, now)
 	if errAvailable != nil {
 		m.mu.RUnlock()
 		return nil, nil, errAvailable
@@ -2772,7 +2843,7 @@ func (m *Manager) pickNext(ctx context.Context, provider, model string, opts cli
 		if selected == nil {
 			return nil, nil, &Error{Code: "auth_not_found", Message: "selector returned no auth"}
 		}
-		if disallowFreeAuth && isFreeCodexAuth(selected) {
+		if (disallowFreeAuth && isFreeCodexAuth(selected)) || maintenanceBlocksAuth(selected, time.Now()) {
 			if tried == nil {
 				tried = make(map[string]struct{})
 			}
@@ -2819,6 +2890,7 @@ func (m *Manager) pickNextMixedLegacy(ctx context.Context, providers []string, m
 		}
 	}
 	registryRef := registry.GetGlobalRegistry()
+	now := time.Now()
 	for _, candidate := range m.auths {
 		if candidate == nil || candidate.Disabled {
 			continue
@@ -2829,6 +2901,9 @@ func (m *Manager) pickNextMixedLegacy(ctx context.Context, providers []string, m
 		if disallowFreeAuth && isFreeCodexAuth(candidate) {
 			continue
 		}
+		if offlineBlocksAuth(candidate) || maintenanceBlocksAuth(candidate, now) {
+			continue
+		}
 		providerKey := strings.TrimSpace(strings.ToLower(candidate.Provider))
 		if providerKey == "" {
 			continue
@@ -2851,7 +2926,7 @@ func (m *Manager) pickNextMixedLegacy(ctx context.Context, providers []string, m
 		m.mu.RUnlock()
 		return nil, nil, "", &Error{Code: "auth_not_found", Message: "no auth available"}
 	}
-	available, errAvailable := m.availableAuthsForRouteModel(candidates, "mixed", model, time.Now())
+	available, errAvailable := m.availableAuthsForRouteModel(candidates, "mixed", model, now)
 	if errAvailable != nil {
 		m.mu.RUnlock()
 		return nil, nil, "", errAvailable
@@ -2945,7 +3020,7 @@ func (m *Manager) pickNextMixed(ctx context.Context, providers []string, model s
 		if selected == nil {
 			return nil, nil, "", &Error{Code: "auth_not_found", Message: "selector returned no auth"}
 		}
-		if disallowFreeAuth && isFreeCodexAuth(selected) {
+		if (disallowFreeAuth && isFreeCodexAuth(selected)) || offlineBlocksAuth(selected) || maintenanceBlocksAuth(selected, time.Now()) {
 			if tried == nil {
 				tried = make(map[string]struct{})
 			}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/maintenance"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/registry"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/executor"
+)
+
+func TestManagerExecute_MaintenanceWindowExcludesAuth(t *testing.T) {
+	const model = "maintenance-test-model"
+
+	// An every-minute schedule keeps the window open for the duration of the test.
+	maintenance.Apply([]maintenance.Window{{Auth: "owner@example.com", Schedule: "* * * * *", Duration: time.Hour}})
+	t.Cleanup(func() { maintenance.Apply(nil) })
+
+	manager := NewManager(nil, nil, nil)
+	exec := &aliasRoutingExecutor{id: "claude"}
+	manager.RegisterExecutor(exec)
+
+	auth := &Auth{ID: "maintenance-auth", Provider: "claude", Status: StatusActive, Metadata: map[string]any{"email": "owner@example.com"}}
+	if _, errRegister := manager.Register(context.Background(), auth); errRegister != nil {
+		t.Fatalf("register auth: %v", errRegister)
+	}
+	reg := registry.GetGlobalRegistry()
+	reg.RegisterClient(auth.ID, auth.Provider, []*registry.ModelInfo{{ID: model}})
+	manager.RefreshSchedulerEntry(auth.ID)
+	t.Cleanup(func() { reg.UnregisterClient(auth.ID) })
+
+	if _, errExecute := manager.Execute(context.Background(), []string{"claude"}, cliproxyexecutor.Request{Model: model}, cliproxyexecutor.Options{}); errExecute == nil {
+		t.Fatal("execute succeeded while the only auth is in a maintenance window")
+	}
+	if got := exec.ExecuteModels(); len(got) != 0 {
+		t.Fatalf("executor should not be called during maintenance, got %v", got)
+	}
+
+	maintenance.Apply(nil)
+	if _, errExecute := manager.Execute(context.Background(), []string{"claude"}, cliproxyexecutor.Request{Model: model}, cliproxyexecutor.Options{}); errExecute != nil {
+		t.Fatalf("execute error after window closed = %v, want success", errExecute)
+	}
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/offline"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/registry"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/executor"
+)
+
+func TestManagerExecute_OfflineModeRefusesRemoteAndAllowsLocal(t *testing.T) {
+	const model = "offline-test-model"
+
+	offline.Apply(offline.Settings{Forced: true})
+	t.Cleanup(func() { offline.Apply(offline.Settings{}) })
+
+	manager := NewManager(nil, nil, nil)
+	remoteExec := &aliasRoutingExecutor{id: "claude"}
+	localExec := &aliasRoutingExecutor{id: "ollama"}
+	manager.RegisterExecutor(remoteExec)
+	manager.RegisterExecutor(localExec)
+
+	remote := &Auth{ID: "offline-remote", Provider: "claude", Status: StatusActive, Attributes: map[string]string{"base_url": "https://api.anthropic.com"}}
+	local := &Auth{ID: "offline-local", Provider: "ollama", Status: StatusActive, Attributes: map[string]string{"base_url": "http://127.0.0.1:11434/v1"}}
+	reg := registry.GetGlobalRegistry()
+	for _, a := range []*Auth{remote, local} {
+		if _, errRegister := manager.Register(context.Background(), a); errRegister != nil {
+			t.Fatalf("register auth: %v", errRegister)
+		}
+		reg.RegisterClient(a.ID, a.Provider, []*registry.ModelInfo{{ID: model}})
+		manager.RefreshSchedulerEntry(a.ID)
+	}
+	t.Cleanup(func() {
+		reg.UnregisterClient(remote.ID)
+		reg.UnregisterClient(local.ID)
+	})
+
+	_, errExecute := manager.Execute(context.Background(), []string{"claude"}, cliproxyexecutor.Request{Model: model}, cliproxyexecutor.Options{})
+	var authErr *Error
+	if !errors.As(errExecute, &authErr) || authErr.Code != ErrorCodeOffline {
+		t.Fatalf("execute error = %v, want %s", errExecute, ErrorCodeOffline)
+	}
+	if authErr.StatusCode() != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", authErr.StatusCode(), http.StatusServiceUnavailable)
+	}
+	if got := remoteExec.ExecuteModels(); len(got) != 0 {
+		t.Fatalf("remote executor should not be called while offline, got %v", got)
+	}
+
+	if _, errExecute = manager.Execute(context.Background(), []string{"ollama"}, cliproxyexecutor.Request{Model: model}, cliproxyexecutor.Options{}); errExecute != nil {
+		t.Fatalf("local execute error = %v, want success", errExecute)
+	}
+}
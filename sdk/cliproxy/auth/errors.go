@@ -1,5 +1,17 @@
 package auth
 
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// ErrorCodeOffline identifies requests refused because offline mode is active.
+	ErrorCodeOffline = "offline_mode"
+	// ErrorCodeModelCooldown identifies requests refused because every credential for the model is cooling down.
+	ErrorCodeModelCooldown = "model_cooldown"
+)
+
 // Error describes an authentication related failure in a provider agnostic format.
 type Error struct {
 	// Code is a short machine readable identifier.
@@ -30,3 +42,13 @@ func (e *Error) StatusCode() int {
 	}
 	return e.HTTPStatus
 }
+
+// CooldownRetryAfter reports whether err signals that every credential for the requested
+// model is cooling down and, if so, how long until the earliest one becomes usable again.
+func CooldownRetryAfter(err error) (time.Duration, bool) {
+	var cooldownErr *modelCooldownError
+	if !errors.As(err, &cooldownErr) || cooldownErr == nil {
+		return 0, false
+	}
+	return cooldownErr.resetIn, true
+}
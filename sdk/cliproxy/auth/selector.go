@@ -80,8 +80,11 @@ func (e *modelCooldownError) Error() string {
 	} else {
 		displayDuration = displayDuration.Round(time.Second)
 	}
+	// The OpenAI-style "insufficient_quota" type lets OpenAI clients classify the refusal
+	// as a quota condition; the code keeps it distinguishable from upstream quota errors.
 	errorBody := map[string]any{
-		"code":          "model_cooldown",
+		"code":          ErrorCodeModelCooldown,
+		"type":          "insufficient_quota",
 		"message":       message,
 		"model":         e.model,
 		"reset_time":    displayDuration.String(),
@@ -5,6 +5,7 @@ package cliproxy
 
 import (
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
@@ -49,6 +50,9 @@ type Builder struct {
 
 	// serverOptions contains additional server configuration options.
 	serverOptions []api.ServerOption
+
+	// listener is an optional pre-built listener the server accepts connections on.
+	listener net.Listener
 }
 
 // Hooks allows callers to plug into service lifecycle stages.
@@ -164,6 +168,31 @@ func (b *Builder) WithPostAuthHook(hook coreauth.PostAuthHook) *Builder {
 	return b
 }
 
+// WithListener serves the API on a pre-built listener, such as one inherited through
+// systemd socket activation or opened on an ephemeral port in tests. The configured
+// host and port are then ignored for serving.
+func (b *Builder) WithListener(ln net.Listener) *Builder {
+	if ln == nil {
+		return b
+	}
+	b.listener = ln
+	b.serverOptions = append(b.serverOptions, api.WithListener(ln))
+	return b
+}
+
+// WithoutDefaultMiddleware disables the built-in Gin request logging and panic recovery
+// middleware, for hosts that provide their own.
+func (b *Builder) WithoutDefaultMiddleware() *Builder {
+	b.serverOptions = append(b.serverOptions, api.WithoutDefaultMiddleware())
+	return b
+}
+
+// WithBasePath mounts all API routes under prefix (for example "/llm").
+func (b *Builder) WithBasePath(prefix string) *Builder {
+	b.serverOptions = append(b.serverOptions, api.WithBasePath(prefix))
+	return b
+}
+
 // Build validates inputs, applies defaults, and returns a ready-to-run service.
 func (b *Builder) Build() (*Service, error) {
 	if b.cfg == nil {
@@ -255,6 +284,7 @@ func (b *Builder) Build() (*Service, error) {
 		accessManager:  accessManager,
 		coreManager:    coreManager,
 		serverOptions:  append([]api.ServerOption(nil), b.serverOptions...),
+		listener:       b.listener,
 	}
 	return service, nil
 }
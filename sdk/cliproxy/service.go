@@ -5,25 +5,39 @@ package cliproxy
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/agentdebug"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/api"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/loadshed"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/maintenance"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/offline"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/pacing"
 	_ "github.com/router-for-me/CLIProxyAPI/v6/internal/redisqueue"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/registry"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/runtime/executor"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/thinking"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/translatordebug"
 	_ "github.com/router-for-me/CLIProxyAPI/v6/internal/usage"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/util"
+	"github.com/router-for-me/CLIProxyAPI/v6/internal/warmup"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/watcher"
 	"github.com/router-for-me/CLIProxyAPI/v6/internal/wsrelay"
 	sdkaccess "github.com/router-for-me/CLIProxyAPI/v6/sdk/access"
 	sdkAuth "github.com/router-for-me/CLIProxyAPI/v6/sdk/auth"
 	coreauth "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/auth"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/executor"
 	"github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/usage"
 	"github.com/router-for-me/CLIProxyAPI/v6/sdk/config"
+	sdktranslator "github.com/router-for-me/CLIProxyAPI/v6/sdk/translator"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -55,6 +69,9 @@ type Service struct {
 	// serverOptions contains additional server configuration options.
 	serverOptions []api.ServerOption
 
+	// listener is the optional pre-built listener supplied through the builder.
+	listener net.Listener
+
 	// server is the HTTP API server instance.
 	server *api.Server
 
@@ -85,6 +102,9 @@ type Service struct {
 	// coreManager handles core authentication and execution.
 	coreManager *coreauth.Manager
 
+	// groqDiscovery tracks Groq auths with model discovery in flight.
+	groqDiscovery sync.Map
+
 	// shutdownOnce ensures shutdown is called only once.
 	shutdownOnce sync.Once
 
@@ -350,6 +370,175 @@ func (s *Service) applyRetryConfig(cfg *config.Config) {
 	s.coreManager.SetRetryConfig(cfg.RequestRetry, maxInterval, cfg.MaxRetryCredentials)
 }
 
+func (s *Service) applyOfflineConfig(cfg *config.Config) {
+	if s == nil || cfg == nil {
+		return
+	}
+	offline.Apply(offline.Settings{
+		Forced:         cfg.Offline.Enabled,
+		AutoDetect:     cfg.Offline.AutoDetect,
+		ProbeAddr:      cfg.Offline.ProbeAddr,
+		ProbeInterval:  cfg.Offline.GetProbeInterval(),
+		LocalProviders: cfg.Offline.LocalProviders,
+	})
+}
+
+func (s *Service) applyAgentDebugConfig(cfg *config.Config) {
+	if s == nil || cfg == nil {
+		return
+	}
+	agentdebug.Configure(cfg.AgentDebug.Enabled, cfg.AgentDebug.MaxEntries, cfg.AgentDebug.MaxEntryBytes)
+}
+
+// applyTranslatorDebugConfig installs translator failure tracking and the quarantine settings.
+func (s *Service) applyTranslatorDebugConfig(cfg *config.Config) {
+	if s == nil || cfg == nil {
+		return
+	}
+	dir := strings.TrimSpace(cfg.TranslatorDebug.Dir)
+	if dir == "" && cfg.TranslatorDebug.Quarantine {
+		if authDir, err := util.ResolveAuthDir(cfg.AuthDir); err == nil && authDir != "" {
+			dir = filepath.Join(authDir, "translator-quarantine")
+		}
+	}
+	translatordebug.Configure(translatordebug.Settings{
+		Quarantine:     cfg.TranslatorDebug.Quarantine,
+		Dir:            dir,
+		SampleRate:     cfg.TranslatorDebug.SampleRate,
+		MaxSamples:     cfg.TranslatorDebug.MaxSamples,
+		MaxSampleBytes: cfg.TranslatorDebug.MaxSampleBytes,
+	})
+}
+
+func (s *Service) applyLoadSheddingConfig(cfg *config.Config) {
+	if s == nil || cfg == nil {
+		return
+	}
+	loadshed.Apply(loadshed.Settings{
+		Enabled:            cfg.LoadShedding.Enabled,
+		MaxCPUPercent:      cfg.LoadShedding.MaxCPUPercent,
+		MaxMemoryPercent:   cfg.LoadShedding.MaxMemoryPercent,
+		MaxProcessMemoryMB: cfg.LoadShedding.MaxProcessMemoryMB,
+		SampleInterval:     cfg.LoadShedding.GetSampleInterval(),
+		RetryAfter:         cfg.LoadShedding.GetRetryAfter(),
+		LowPriorityPaths:   cfg.LoadShedding.GetLowPriorityPaths(),
+	})
+}
+
+// applyClientPacingConfig installs the per-client budgets advertised in x-ratelimit-* headers.
+func (s *Service) applyClientPacingConfig(cfg *config.Config) {
+	if s == nil || cfg == nil {
+		return
+	}
+	pacing.Apply(pacing.Settings{
+		Enabled:           cfg.ClientPacing.Enabled,
+		RequestsPerMinute: cfg.ClientPacing.RequestsPerMinute,
+		TokensPerMinute:   cfg.ClientPacing.TokensPerMinute,
+		Enforce:           cfg.ClientPacing.Enforce,
+	})
+}
+
+// applyMaintenanceWindows installs the per-account maintenance windows used during routing.
+func (s *Service) applyMaintenanceWindows(cfg *config.Config) {
+	if s == nil || cfg == nil {
+		return
+	}
+	windows := make([]maintenance.Window, 0, len(cfg.MaintenanceWindows))
+	for _, w := range cfg.MaintenanceWindows {
+		windows = append(windows, maintenance.Window{
+			Auth:     w.Auth,
+			Schedule: w.Schedule,
+			Duration: time.Duration(w.DurationMinutes) * time.Minute,
+			Timezone: w.Timezone,
+		})
+	}
+	maintenance.Apply(windows)
+}
+
+// applyWarmupConfig (re)starts model warm-up for the configured models. Targets are
+// resolved after the configured delay so accounts loaded by the watcher are included.
+func (s *Service) applyWarmupConfig(cfg *config.Config) {
+	if s == nil || cfg == nil || s.coreManager == nil {
+		return
+	}
+	if !cfg.Warmup.Enabled || len(cfg.Warmup.Models) == 0 {
+		warmup.Stop()
+		return
+	}
+	models := append([]string(nil), cfg.Warmup.Models...)
+	prompt := cfg.Warmup.GetPrompt()
+	warmup.Start(func() []warmup.Target {
+		return s.warmupTargets(models)
+	}, func(ctx context.Context, target warmup.Target) error {
+		return s.executeWarmup(ctx, target, prompt)
+	}, warmup.Options{
+		Delay:       cfg.Warmup.GetDelay(),
+		Timeout:     cfg.Warmup.GetTimeout(),
+		Concurrency: cfg.Warmup.GetConcurrency(),
+	})
+}
+
+// warmupTargets pairs each model with every enabled account registered for it.
+func (s *Service) warmupTargets(models []string) []warmup.Target {
+	reg := registry.GetGlobalRegistry()
+	auths := s.coreManager.List()
+	var targets []warmup.Target
+	for _, model := range models {
+		model = strings.TrimSpace(model)
+		if model == "" {
+			continue
+		}
+		matched := 0
+		for _, auth := range auths {
+			if auth == nil || auth.ID == "" || auth.Disabled || auth.Status == coreauth.StatusDisabled {
+				continue
+			}
+			if !reg.ClientSupportsModel(auth.ID, model) {
+				continue
+			}
+			targets = append(targets, warmup.Target{Model: model, AuthID: auth.ID, Provider: auth.Provider})
+			matched++
+		}
+		if matched == 0 {
+			log.Warnf("warm-up: no enabled account serves model %s", model)
+		}
+	}
+	return targets
+}
+
+// executeWarmup sends a minimal OpenAI-format chat request pinned to the target account.
+func (s *Service) executeWarmup(ctx context.Context, target warmup.Target, prompt string) error {
+	payload, _ := json.Marshal(map[string]any{
+		"model":      target.Model,
+		"messages":   []map[string]string{{"role": "user", "content": prompt}},
+		"max_tokens": 16,
+		"stream":     false,
+	})
+	_, err := s.coreManager.Execute(ctx, []string{target.Provider}, cliproxyexecutor.Request{
+		Model:   target.Model,
+		Payload: payload,
+	}, cliproxyexecutor.Options{
+		OriginalRequest: payload,
+		SourceFormat:    sdktranslator.FromString("openai"),
+		Metadata: map[string]any{
+			cliproxyexecutor.PinnedAuthMetadataKey:     target.AuthID,
+			cliproxyexecutor.RequestedModelMetadataKey: target.Model,
+		},
+	})
+	return err
+}
+
+func (s *Service) applyThinkingLevelsConfig(cfg *config.Config) {
+	if s == nil || cfg == nil {
+		return
+	}
+	rules := make([]thinking.LevelBudgetRule, 0, len(cfg.ThinkingLevels.Models))
+	for _, rule := range cfg.ThinkingLevels.Models {
+		rules = append(rules, thinking.LevelBudgetRule{Name: rule.Name, Budgets: rule.Budgets})
+	}
+	thinking.SetLevelBudgets(cfg.ThinkingLevels.Budgets, rules)
+}
+
 func openAICompatInfoFromAuth(a *coreauth.Auth) (providerKey string, compatName string, ok bool) {
 	if a == nil {
 		return "", "", false
@@ -425,6 +614,8 @@ func (s *Service) ensureExecutorsForAuthWithMode(a *coreauth.Auth, forceReplace
 		s.coreManager.RegisterExecutor(executor.NewClaudeExecutor(s.cfg))
 	case "kimi":
 		s.coreManager.RegisterExecutor(executor.NewKimiExecutor(s.cfg))
+	case "groq":
+		s.coreManager.RegisterExecutor(executor.NewGroqExecutor(s.cfg))
 	default:
 		providerKey := strings.ToLower(strings.TrimSpace(a.Provider))
 		if providerKey == "" {
@@ -495,6 +686,13 @@ func (s *Service) Run(ctx context.Context) error {
 	}
 
 	s.applyRetryConfig(s.cfg)
+	s.applyOfflineConfig(s.cfg)
+	s.applyAgentDebugConfig(s.cfg)
+	s.applyTranslatorDebugConfig(s.cfg)
+	s.applyThinkingLevelsConfig(s.cfg)
+	s.applyLoadSheddingConfig(s.cfg)
+	s.applyMaintenanceWindows(s.cfg)
+	s.applyClientPacingConfig(s.cfg)
 
 	if s.coreManager != nil {
 		if errLoad := s.coreManager.Load(ctx); errLoad != nil {
@@ -600,7 +798,13 @@ func (s *Service) Run(ctx context.Context) error {
 	}()
 
 	time.Sleep(100 * time.Millisecond)
-	fmt.Printf("API server started successfully on: %s:%d\n", s.cfg.Host, s.cfg.Port)
+	if s.listener != nil {
+		fmt.Printf("API server started successfully on: %s\n", s.listener.Addr())
+	} else if len(s.cfg.Listen) > 0 {
+		fmt.Printf("API server started successfully on: %s\n", strings.Join(s.cfg.Listen, ", "))
+	} else {
+		fmt.Printf("API server started successfully on: %s:%d\n", s.cfg.Host, s.cfg.Port)
+	}
 
 	s.applyPprofConfig(s.cfg)
 
@@ -675,6 +879,13 @@ func (s *Service) Run(ctx context.Context) error {
 		}
 
 		s.applyRetryConfig(newCfg)
+		s.applyOfflineConfig(newCfg)
+		s.applyAgentDebugConfig(newCfg)
+		s.applyTranslatorDebugConfig(newCfg)
+		s.applyThinkingLevelsConfig(newCfg)
+		s.applyLoadSheddingConfig(newCfg)
+		s.applyMaintenanceWindows(newCfg)
+		s.applyClientPacingConfig(newCfg)
 		s.applyPprofConfig(newCfg)
 		if s.server != nil {
 			s.server.UpdateClients(newCfg)
@@ -687,6 +898,7 @@ func (s *Service) Run(ctx context.Context) error {
 			s.coreManager.SetOAuthModelAlias(newCfg.OAuthModelAlias)
 		}
 		s.rebindExecutors()
+		s.applyWarmupConfig(newCfg)
 	}
 
 	watcherWrapper, err = s.watcherFactory(s.configPath, s.cfg.AuthDir, reloadCallback)
@@ -706,6 +918,7 @@ func (s *Service) Run(ctx context.Context) error {
 		return fmt.Errorf("cliproxy: failed to start watcher: %w", err)
 	}
 	log.Info("file watcher started for config and auth directory changes")
+	s.applyWarmupConfig(s.cfg)
 
 	// Prefer core auth manager auto refresh if available.
 	if s.coreManager != nil {
@@ -750,6 +963,9 @@ func (s *Service) Shutdown(ctx context.Context) error {
 		if s.coreManager != nil {
 			s.coreManager.StopAutoRefresh()
 		}
+		offline.Stop()
+		warmup.Stop()
+		loadshed.Stop()
 		if s.watcher != nil {
 			if err := s.watcher.Stop(); err != nil {
 				log.Errorf("failed to stop file watcher: %v", err)
@@ -928,6 +1144,17 @@ func (s *Service) registerModelsForAuth(a *coreauth.Auth) {
 	case "kimi":
 		models = registry.GetKimiModels()
 		models = applyExcludedModels(models, excluded)
+	case "groq":
+		entry := s.resolveConfigGroqKey(a)
+		if entry != nil && len(entry.Models) > 0 {
+			models = buildGroqConfigModels(entry)
+		} else if models = executor.CachedGroqModels(a); models == nil {
+			s.discoverGroqModels(a)
+		}
+		if entry != nil && authKind == "apikey" {
+			excluded = entry.ExcludedModels
+		}
+		models = applyExcludedModels(models, excluded)
 	default:
 		// Handle OpenAI-compatibility providers by name using config
 		if s.cfg != nil {
@@ -1119,6 +1346,53 @@ func (s *Service) resolveConfigClaudeKey(auth *coreauth.Auth) *config.ClaudeKey
 	return nil
 }
 
+func (s *Service) resolveConfigGroqKey(auth *coreauth.Auth) *config.GroqKey {
+	if auth == nil || s.cfg == nil {
+		return nil
+	}
+	var attrKey, attrBase string
+	if auth.Attributes != nil {
+		attrKey = strings.TrimSpace(auth.Attributes["api_key"])
+		attrBase = strings.TrimSpace(auth.Attributes["base_url"])
+	}
+	for i := range s.cfg.GroqKey {
+		entry := &s.cfg.GroqKey[i]
+		if strings.EqualFold(strings.TrimSpace(entry.APIKey), attrKey) && strings.EqualFold(strings.TrimSpace(entry.BaseURL), attrBase) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// discoverGroqModels fetches the model list for a Groq key in the background and
+// re-registers the auth once models are known. Failed discovery is retried on the
+// next registration pass (e.g. config reload).
+func (s *Service) discoverGroqModels(a *coreauth.Auth) {
+	if a == nil || a.ID == "" {
+		return
+	}
+	if _, loaded := s.groqDiscovery.LoadOrStore(a.ID, struct{}{}); loaded {
+		return
+	}
+	auth := a.Clone()
+	go func() {
+		defer s.groqDiscovery.Delete(auth.ID)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		models, err := executor.FetchGroqModels(ctx, s.cfg, auth)
+		if err != nil {
+			log.Warnf("groq model discovery failed for %s: %v", auth.ID, err)
+			return
+		}
+		if len(models) == 0 {
+			log.Warnf("groq model discovery returned no models for %s", auth.ID)
+			return
+		}
+		log.Infof("discovered %d groq models for %s", len(models), auth.ID)
+		s.refreshModelRegistrationForAuth(auth)
+	}()
+}
+
 func (s *Service) resolveConfigGeminiKey(auth *coreauth.Auth) *config.GeminiKey {
 	if auth == nil || s.cfg == nil {
 		return nil
@@ -1410,6 +1684,13 @@ func buildClaudeConfigModels(entry *config.ClaudeKey) []*ModelInfo {
 	return buildConfigModels(entry.Models, "anthropic", "claude")
 }
 
+func buildGroqConfigModels(entry *config.GroqKey) []*ModelInfo {
+	if entry == nil {
+		return nil
+	}
+	return buildConfigModels(entry.Models, "groq", "groq")
+}
+
 func buildCodexConfigModels(entry *config.CodexKey) []*ModelInfo {
 	if entry == nil {
 		return nil
@@ -23,6 +23,8 @@ type PayloadModelRule = internalconfig.PayloadModelRule
 type GeminiKey = internalconfig.GeminiKey
 type CodexKey = internalconfig.CodexKey
 type ClaudeKey = internalconfig.ClaudeKey
+type GroqKey = internalconfig.GroqKey
+type GroqModel = internalconfig.GroqModel
 type VertexCompatKey = internalconfig.VertexCompatKey
 type VertexCompatModel = internalconfig.VertexCompatModel
 type OpenAICompatibility = internalconfig.OpenAICompatibility
@@ -31,6 +33,15 @@ type OpenAICompatibilityModel = internalconfig.OpenAICompatibilityModel
 
 type TLS = internalconfig.TLSConfig
 
+type OfflineConfig = internalconfig.OfflineConfig
+type AgentDebugConfig = internalconfig.AgentDebugConfig
+type ThinkingLevelsConfig = internalconfig.ThinkingLevelsConfig
+type ThinkingLevelModelRule = internalconfig.ThinkingLevelModelRule
+type WarmupConfig = internalconfig.WarmupConfig
+type LoadSheddingConfig = internalconfig.LoadSheddingConfig
+type MaintenanceWindow = internalconfig.MaintenanceWindow
+type ClientPacingConfig = internalconfig.ClientPacingConfig
+
 type AccessConfig = internalconfig.AccessConfig
 type AccessProvider = internalconfig.AccessProvider
 
@@ -23,3 +23,12 @@ func NewFileRequestLogger(enabled bool, logsDir string, configDir string) *FileR
 func NewFileRequestLoggerWithOptions(enabled bool, logsDir string, configDir string, errorLogsMaxFiles int) *FileRequestLogger {
 	return internallogging.NewFileRequestLogger(enabled, logsDir, configDir, errorLogsMaxFiles)
 }
+
+// AsyncRequestLogger queues non-streaming request logs in a bounded buffer written in the background.
+type AsyncRequestLogger = internallogging.AsyncRequestLogger
+
+// NewAsyncRequestLogger wraps inner so request logs are written off the request path.
+// When the buffer is full the oldest queued entry is dropped. Call Close to flush.
+func NewAsyncRequestLogger(inner *FileRequestLogger, bufferSize int) *AsyncRequestLogger {
+	return internallogging.NewAsyncRequestLogger(inner, bufferSize)
+}
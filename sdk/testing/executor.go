@@ -0,0 +1,163 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	coreauth "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/auth"
+	cliproxyexecutor "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/executor"
+	"github.com/tidwall/sjson"
+)
+
+// DefaultText is the reply produced by a FakeExecutor when no Text is configured.
+const DefaultText = "Hello from the fake provider."
+
+// Call records a single request observed by a FakeExecutor.
+type Call struct {
+	// AuthID is the credential selected by the auth manager.
+	AuthID string
+	// Model is the upstream model identifier passed to the executor.
+	Model string
+	// Payload is the request body received by the executor.
+	Payload []byte
+	// Stream reports whether the call used ExecuteStream.
+	Stream bool
+}
+
+// FakeExecutor is a ProviderExecutor that never touches the network.
+//
+// By default it answers with an OpenAI-compatible chat completion containing Text,
+// and streams the same text one word per chunk. Responses are fully deterministic:
+// IDs and timestamps are fixed so tests can compare bodies byte-for-byte.
+type FakeExecutor struct {
+	// Provider is the provider key handled by the executor.
+	Provider string
+	// Text is the assistant reply used by the default response builders.
+	Text string
+	// Response, when set, is returned verbatim from Execute.
+	Response []byte
+	// Chunks, when set, are emitted verbatim and in order from ExecuteStream.
+	Chunks [][]byte
+	// Err, when set, is returned from Execute and ExecuteStream instead of a response.
+	Err error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewFakeExecutor returns a FakeExecutor for provider that replies with DefaultText.
+func NewFakeExecutor(provider string) *FakeExecutor {
+	return &FakeExecutor{Provider: provider, Text: DefaultText}
+}
+
+// Identifier returns the provider key handled by this executor.
+func (e *FakeExecutor) Identifier() string { return e.Provider }
+
+// Execute records the call and returns the configured or default non-streaming response.
+func (e *FakeExecutor) Execute(_ context.Context, auth *coreauth.Auth, req cliproxyexecutor.Request, _ cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
+	e.record(auth, req, false)
+	if e.Err != nil {
+		return cliproxyexecutor.Response{}, e.Err
+	}
+	if e.Response != nil {
+		return cliproxyexecutor.Response{Payload: append([]byte(nil), e.Response...)}, nil
+	}
+	return cliproxyexecutor.Response{Payload: e.completion(req.Model)}, nil
+}
+
+// ExecuteStream records the call and emits the configured or default chunks in order.
+func (e *FakeExecutor) ExecuteStream(ctx context.Context, auth *coreauth.Auth, req cliproxyexecutor.Request, _ cliproxyexecutor.Options) (*cliproxyexecutor.StreamResult, error) {
+	e.record(auth, req, true)
+	if e.Err != nil {
+		return nil, e.Err
+	}
+	chunks := e.Chunks
+	if chunks == nil {
+		chunks = e.completionChunks(req.Model)
+	}
+	ch := make(chan cliproxyexecutor.StreamChunk, len(chunks))
+	for _, chunk := range chunks {
+		ch <- cliproxyexecutor.StreamChunk{Payload: append([]byte(nil), chunk...)}
+	}
+	close(ch)
+	return &cliproxyexecutor.StreamResult{Headers: http.Header{}, Chunks: ch}, nil
+}
+
+// Refresh returns the auth unchanged.
+func (e *FakeExecutor) Refresh(_ context.Context, auth *coreauth.Auth) (*coreauth.Auth, error) {
+	return auth, nil
+}
+
+// CountTokens returns a fixed OpenAI-style token count derived from the payload length.
+func (e *FakeExecutor) CountTokens(_ context.Context, _ *coreauth.Auth, req cliproxyexecutor.Request, _ cliproxyexecutor.Options) (cliproxyexecutor.Response, error) {
+	return cliproxyexecutor.Response{Payload: []byte(fmt.Sprintf(`{"total_tokens":%d}`, (len(req.Payload)+3)/4))}, nil
+}
+
+// HttpRequest is not supported by the fake executor.
+func (e *FakeExecutor) HttpRequest(context.Context, *coreauth.Auth, *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("fake executor %s: raw HTTP requests are not supported", e.Provider)
+}
+
+// Calls returns a copy of the requests observed so far.
+func (e *FakeExecutor) Calls() []Call {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]Call, len(e.calls))
+	copy(out, e.calls)
+	return out
+}
+
+// Reset clears the recorded calls.
+func (e *FakeExecutor) Reset() {
+	e.mu.Lock()
+	e.calls = nil
+	e.mu.Unlock()
+}
+
+func (e *FakeExecutor) record(auth *coreauth.Auth, req cliproxyexecutor.Request, stream bool) {
+	call := Call{Model: req.Model, Payload: append([]byte(nil), req.Payload...), Stream: stream}
+	if auth != nil {
+		call.AuthID = auth.ID
+	}
+	e.mu.Lock()
+	e.calls = append(e.calls, call)
+	e.mu.Unlock()
+}
+
+func (e *FakeExecutor) text() string {
+	if e.Text == "" {
+		return DefaultText
+	}
+	return e.Text
+}
+
+func (e *FakeExecutor) completion(model string) []byte {
+	out := []byte(`{"id":"chatcmpl-fake","object":"chat.completion","created":0,"model":"","choices":[{"index":0,"message":{"role":"assistant","content":""},"finish_reason":"stop"}],"usage":{"prompt_tokens":0,"completion_tokens":0,"total_tokens":0}}`)
+	words := len(strings.Fields(e.text()))
+	out, _ = sjson.SetBytes(out, "model", model)
+	out, _ = sjson.SetBytes(out, "choices.0.message.content", e.text())
+	out, _ = sjson.SetBytes(out, "usage.completion_tokens", words)
+	out, _ = sjson.SetBytes(out, "usage.total_tokens", words)
+	return out
+}
+
+func (e *FakeExecutor) completionChunks(model string) [][]byte {
+	base := []byte(`{"id":"chatcmpl-fake","object":"chat.completion.chunk","created":0,"model":"","choices":[{"index":0,"delta":{},"finish_reason":null}]}`)
+	base, _ = sjson.SetBytes(base, "model", model)
+
+	words := strings.SplitAfter(e.text(), " ")
+	chunks := make([][]byte, 0, len(words)+1)
+	for i, word := range words {
+		chunk := append([]byte(nil), base...)
+		if i == 0 {
+			chunk, _ = sjson.SetBytes(chunk, "choices.0.delta.role", "assistant")
+		}
+		chunk, _ = sjson.SetBytes(chunk, "choices.0.delta.content", word)
+		chunks = append(chunks, chunk)
+	}
+	final, _ := sjson.SetBytes(append([]byte(nil), base...), "choices.0.finish_reason", "stop")
+	return append(chunks, final)
+}
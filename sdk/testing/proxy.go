@@ -0,0 +1,313 @@
+// Package testing spins up an in-process ProxyPilot for integration tests.
+//
+// It wires the regular SDK service (handlers, access control, auth manager and
+// model registry) to FakeExecutor instances so downstream projects embedding
+// the SDK can exercise their integration end-to-end without real credentials
+// or network access. Import it under an alias to avoid clashing with the
+// standard library:
+//
+//	import proxytest "github.com/router-for-me/CLIProxyAPI/v6/sdk/testing"
+//
+//	func TestClient(t *testing.T) {
+//		p := proxytest.Start(t, proxytest.Options{
+//			Providers: []proxytest.Provider{{Executor: proxytest.NewFakeExecutor("myprov"), Models: []string{"myprov-pro-1"}}},
+//		})
+//		// point the client under test at p.URL using p.APIKey
+//	}
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/router-for-me/CLIProxyAPI/v6/sdk/api"
+	"github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy"
+	coreauth "github.com/router-for-me/CLIProxyAPI/v6/sdk/cliproxy/auth"
+	"github.com/router-for-me/CLIProxyAPI/v6/sdk/config"
+)
+
+// DefaultAPIKey is the client key accepted by a Proxy when Options.APIKey is empty.
+const DefaultAPIKey = "proxytest-key"
+
+const startTimeout = 10 * time.Second
+
+// proxySeq keeps auth and registry client IDs unique across proxies in one process.
+var proxySeq atomic.Int64
+
+// TB is the subset of testing.TB used by Start.
+type TB interface {
+	Helper()
+	Cleanup(func())
+	Fatalf(format string, args ...any)
+}
+
+// Provider binds an executor to the models it serves.
+type Provider struct {
+	// Executor handles requests for the provider. FakeExecutor is the usual choice,
+	// but any coreauth.ProviderExecutor works.
+	Executor coreauth.ProviderExecutor
+	// Models lists the model IDs routed to the executor.
+	Models []string
+	// Accounts is the number of credentials registered for the provider (default 1).
+	// Use more than one to exercise round-robin and failover behaviour.
+	Accounts int
+}
+
+// Options configures an in-process proxy.
+type Options struct {
+	// APIKey is the client key required by the proxy. Defaults to DefaultAPIKey.
+	APIKey string
+	// Providers lists the fake upstreams wired into the proxy.
+	Providers []Provider
+	// Dir is the working directory for the config file and auth store.
+	// A temporary directory is created (and removed on Close) when empty.
+	Dir string
+	// Configure optionally adjusts the configuration before the service is built.
+	Configure func(*config.Config)
+	// ServerOptions are forwarded to the API server.
+	ServerOptions []api.ServerOption
+}
+
+// Proxy is a running in-process ProxyPilot instance.
+type Proxy struct {
+	// URL is the base URL of the proxy, e.g. "http://127.0.0.1:54321".
+	URL string
+	// APIKey is the client key accepted by the proxy.
+	APIKey string
+	// Config is the configuration the service was started with.
+	Config *config.Config
+	// Manager is the core auth manager used for execution.
+	Manager *coreauth.Manager
+	// Service is the underlying SDK service.
+	Service *cliproxy.Service
+
+	clientIDs []string
+	tempDir   string
+	cancel    context.CancelFunc
+	done      chan error
+	closed    atomic.Bool
+}
+
+// Start launches a proxy and registers Close with t.Cleanup.
+// It fails the test immediately if the proxy cannot be started.
+func Start(t TB, opts Options) *Proxy {
+	t.Helper()
+	p, err := New(opts)
+	if err != nil {
+		t.Fatalf("proxytest: start proxy: %v", err)
+		return nil
+	}
+	t.Cleanup(func() {
+		if errClose := p.Close(); errClose != nil {
+			t.Fatalf("proxytest: close proxy: %v", errClose)
+		}
+	})
+	return p
+}
+
+// New launches a proxy and waits until it accepts connections.
+// Callers must call Close when done.
+func New(opts Options) (*Proxy, error) {
+	apiKey := strings.TrimSpace(opts.APIKey)
+	if apiKey == "" {
+		apiKey = DefaultAPIKey
+	}
+
+	p := &Proxy{APIKey: apiKey}
+	dir := opts.Dir
+	if dir == "" {
+		tempDir, errTemp := os.MkdirTemp("", "proxytest-*")
+		if errTemp != nil {
+			return nil, fmt.Errorf("create temp dir: %w", errTemp)
+		}
+		dir = tempDir
+		p.tempDir = tempDir
+	}
+
+	port, errPort := freePort()
+	if errPort != nil {
+		p.cleanupDir()
+		return nil, errPort
+	}
+
+	cfg := &config.Config{}
+	cfg.Host = "127.0.0.1"
+	cfg.Port = port
+	cfg.AuthDir = filepath.Join(dir, "auths")
+	cfg.APIKeys = []string{apiKey}
+	if opts.Configure != nil {
+		opts.Configure(cfg)
+	}
+	configPath := filepath.Join(dir, "config.yaml")
+	if errWrite := os.WriteFile(configPath, []byte(fmt.Sprintf("host: %q\nport: %d\n", cfg.Host, cfg.Port)), 0o600); errWrite != nil {
+		p.cleanupDir()
+		return nil, fmt.Errorf("write config: %w", errWrite)
+	}
+
+	manager := coreauth.NewManager(nil, nil, nil)
+	ready := make(chan struct{})
+	svc, errBuild := cliproxy.NewBuilder().
+		WithConfig(cfg).
+		WithConfigPath(configPath).
+		WithCoreAuthManager(manager).
+		WithWatcherFactory(noopWatcherFactory).
+		WithServerOptions(opts.ServerOptions...).
+		WithHooks(cliproxy.Hooks{OnAfterStart: func(*cliproxy.Service) { close(ready) }}).
+		Build()
+	if errBuild != nil {
+		p.cleanupDir()
+		return nil, fmt.Errorf("build service: %w", errBuild)
+	}
+
+	p.URL = fmt.Sprintf("http://%s", net.JoinHostPort(cfg.Host, fmt.Sprint(cfg.Port)))
+	p.Config = cfg
+	p.Manager = manager
+	p.Service = svc
+
+	if errRegister := p.registerProviders(opts.Providers); errRegister != nil {
+		p.unregisterClients()
+		p.cleanupDir()
+		return nil, errRegister
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done = make(chan error, 1)
+	go func() {
+		p.done <- svc.Run(ctx)
+	}()
+
+	if errWait := p.waitReady(ready); errWait != nil {
+		_ = p.Close()
+		return nil, errWait
+	}
+	return p, nil
+}
+
+// Close stops the proxy, unregisters its models and removes temporary files.
+// It is safe to call more than once.
+func (p *Proxy) Close() error {
+	if p == nil || p.closed.Swap(true) {
+		return nil
+	}
+	var errRun error
+	if p.cancel != nil {
+		p.cancel()
+		errRun = <-p.done
+		if errors.Is(errRun, context.Canceled) {
+			errRun = nil
+		}
+	}
+	p.unregisterClients()
+	p.cleanupDir()
+	return errRun
+}
+
+func (p *Proxy) registerProviders(providers []Provider) error {
+	reg := cliproxy.GlobalModelRegistry()
+	seq := proxySeq.Add(1)
+	for _, provider := range providers {
+		if provider.Executor == nil {
+			return fmt.Errorf("provider executor is required")
+		}
+		key := provider.Executor.Identifier()
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("provider executor identifier is empty")
+		}
+		p.Manager.RegisterExecutor(provider.Executor)
+
+		models := make([]*cliproxy.ModelInfo, 0, len(provider.Models))
+		for _, id := range provider.Models {
+			models = append(models, &cliproxy.ModelInfo{ID: id, Object: "model", OwnedBy: key, Type: key})
+		}
+
+		accounts := provider.Accounts
+		if accounts <= 0 {
+			accounts = 1
+		}
+		for i := 0; i < accounts; i++ {
+			auth := &coreauth.Auth{
+				ID:       fmt.Sprintf("proxytest-%d-%s-%d", seq, key, i),
+				Provider: key,
+				Label:    fmt.Sprintf("%s-%d", key, i),
+				Status:   coreauth.StatusActive,
+			}
+			if _, errRegister := p.Manager.Register(context.Background(), auth); errRegister != nil {
+				return fmt.Errorf("register auth %s: %w", auth.ID, errRegister)
+			}
+			reg.RegisterClient(auth.ID, key, models)
+			p.clientIDs = append(p.clientIDs, auth.ID)
+			p.Manager.RefreshSchedulerEntry(auth.ID)
+		}
+	}
+	return nil
+}
+
+func (p *Proxy) unregisterClients() {
+	reg := cliproxy.GlobalModelRegistry()
+	for _, id := range p.clientIDs {
+		reg.UnregisterClient(id)
+	}
+	p.clientIDs = nil
+}
+
+func (p *Proxy) cleanupDir() {
+	if p.tempDir != "" {
+		_ = os.RemoveAll(p.tempDir)
+		p.tempDir = ""
+	}
+}
+
+func (p *Proxy) waitReady(ready <-chan struct{}) error {
+	deadline := time.NewTimer(startTimeout)
+	defer deadline.Stop()
+	select {
+	case <-ready:
+	case errRun := <-p.done:
+		p.done <- errRun
+		return fmt.Errorf("service exited during startup: %w", errRun)
+	case <-deadline.C:
+		return fmt.Errorf("service did not start within %s", startTimeout)
+	}
+
+	addr := strings.TrimPrefix(p.URL, "http://")
+	for {
+		conn, errDial := net.DialTimeout("tcp", addr, time.Second)
+		if errDial == nil {
+			_ = conn.Close()
+			return nil
+		}
+		select {
+		case errRun := <-p.done:
+			p.done <- errRun
+			return fmt.Errorf("service exited during startup: %w", errRun)
+		case <-deadline.C:
+			return fmt.Errorf("proxy did not accept connections within %s: %w", startTimeout, errDial)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// noopWatcherFactory disables config and auth directory watching; fixtures are static.
+func noopWatcherFactory(string, string, func(*config.Config)) (*cliproxy.WatcherWrapper, error) {
+	return &cliproxy.WatcherWrapper{}, nil
+}
+
+func freePort() (int, error) {
+	listener, errListen := net.Listen("tcp", "127.0.0.1:0")
+	if errListen != nil {
+		return 0, fmt.Errorf("reserve port: %w", errListen)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	if errClose := listener.Close(); errClose != nil {
+		return 0, fmt.Errorf("release port: %w", errClose)
+	}
+	return port, nil
+}
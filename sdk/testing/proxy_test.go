@@ -0,0 +1,79 @@
+package testing_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	proxytest "github.com/router-for-me/CLIProxyAPI/v6/sdk/testing"
+	"github.com/tidwall/gjson"
+)
+
+func postChat(t *testing.T, p *proxytest.Proxy, body string) (int, []byte) {
+	t.Helper()
+	req, errReq := http.NewRequest(http.MethodPost, p.URL+"/v1/chat/completions", bytes.NewBufferString(body))
+	if errReq != nil {
+		t.Fatalf("new request: %v", errReq)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+	resp, errDo := http.DefaultClient.Do(req)
+	if errDo != nil {
+		t.Fatalf("do request: %v", errDo)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, errRead := io.ReadAll(resp.Body)
+	if errRead != nil {
+		t.Fatalf("read body: %v", errRead)
+	}
+	return resp.StatusCode, data
+}
+
+func TestProxyRoutesChatCompletionToFakeExecutor(t *testing.T) {
+	exec := proxytest.NewFakeExecutor("proxytest-fake")
+	exec.Text = "pong"
+	p := proxytest.Start(t, proxytest.Options{
+		Providers: []proxytest.Provider{{Executor: exec, Models: []string{"proxytest-model"}}},
+	})
+
+	status, body := postChat(t, p, `{"model":"proxytest-model","messages":[{"role":"user","content":"ping"}]}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", status, body)
+	}
+	if got := gjson.GetBytes(body, "choices.0.message.content").String(); got != "pong" {
+		t.Fatalf("content = %q, want %q; body = %s", got, "pong", body)
+	}
+	calls := exec.Calls()
+	if len(calls) != 1 || calls[0].Model != "proxytest-model" || calls[0].Stream {
+		t.Fatalf("unexpected calls: %+v", calls)
+	}
+}
+
+func TestProxyStreamsDeterministicChunks(t *testing.T) {
+	exec := proxytest.NewFakeExecutor("proxytest-stream")
+	exec.Chunks = [][]byte{[]byte(`{"n":1}`), []byte(`{"n":2}`)}
+	p := proxytest.Start(t, proxytest.Options{
+		Providers: []proxytest.Provider{{Executor: exec, Models: []string{"proxytest-stream-model"}}},
+	})
+
+	status, body := postChat(t, p, `{"model":"proxytest-stream-model","stream":true,"messages":[{"role":"user","content":"ping"}]}`)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", status, body)
+	}
+	want := "data: {\"n\":1}\n\ndata: {\"n\":2}\n\ndata: [DONE]\n\n"
+	if got := string(body); !strings.HasSuffix(got, want) {
+		t.Fatalf("stream body = %q, want suffix %q", got, want)
+	}
+}
+
+func TestProxyRejectsUnknownAPIKey(t *testing.T) {
+	p := proxytest.Start(t, proxytest.Options{})
+	p.APIKey = "wrong"
+
+	status, _ := postChat(t, p, `{"model":"anything","messages":[]}`)
+	if status != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", status, http.StatusUnauthorized)
+	}
+}
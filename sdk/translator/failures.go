@@ -0,0 +1,113 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/tidwall/gjson"
+)
+
+// FailureKind classifies how a translator failed.
+type FailureKind string
+
+const (
+	// FailurePanic marks a translator that panicked. The panic is re-raised after reporting.
+	FailurePanic FailureKind = "panic"
+	// FailureInvalidJSON marks a translator that returned a non-empty payload that is not valid JSON.
+	FailureInvalidJSON FailureKind = "invalid_json"
+)
+
+// Translation stages reported in Failure.Stage.
+const (
+	StageRequest   = "request"
+	StageStream    = "stream"
+	StageNonStream = "non_stream"
+)
+
+// Failure describes a single translator failure.
+type Failure struct {
+	Stage  string
+	Kind   FailureKind
+	From   Format
+	To     Format
+	Model  string
+	Reason string
+	// Input is the payload handed to the translator.
+	Input []byte
+	// Output is the payload the translator produced; empty for panics.
+	Output []byte
+}
+
+// FailureHook receives translator failures. It must not retain Input or Output after returning.
+type FailureHook func(Failure)
+
+var failureHook atomic.Pointer[FailureHook]
+
+// SetFailureHook installs the callback notified about translator failures; nil removes it.
+func SetFailureHook(hook FailureHook) {
+	if hook == nil {
+		failureHook.Store(nil)
+		return
+	}
+	failureHook.Store(&hook)
+}
+
+func reportFailure(f Failure) {
+	hook := failureHook.Load()
+	if hook == nil {
+		return
+	}
+	(*hook)(f)
+}
+
+// reportPanic forwards a recovered panic to the failure hook and re-raises it.
+func reportPanic(stage string, from, to Format, model string, input []byte) {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+	reportFailure(Failure{
+		Stage:  stage,
+		Kind:   FailurePanic,
+		From:   from,
+		To:     to,
+		Model:  model,
+		Reason: fmt.Sprint(rec),
+		Input:  input,
+	})
+	panic(rec)
+}
+
+// checkOutput reports a failure when a translator produced a non-empty, invalid JSON payload.
+func checkOutput(stage string, from, to Format, model string, input, output []byte) []byte {
+	if len(output) == 0 || failureHook.Load() == nil || gjson.ValidBytes(output) {
+		return output
+	}
+	reportFailure(Failure{
+		Stage:  stage,
+		Kind:   FailureInvalidJSON,
+		From:   from,
+		To:     to,
+		Model:  model,
+		Reason: ErrInvalidJSON.Error(),
+		Input:  input,
+		Output: output,
+	})
+	return output
+}
+
+func runRequest(fn RequestTransform, from, to Format, model string, rawJSON []byte, stream bool) []byte {
+	defer reportPanic(StageRequest, from, to, model, rawJSON)
+	return checkOutput(StageRequest, from, to, model, rawJSON, fn(model, rawJSON, stream))
+}
+
+func runStream(ctx context.Context, fn ResponseTransform, from, to Format, model string, originalRequestRawJSON, requestRawJSON, rawJSON []byte, param *any) [][]byte {
+	defer reportPanic(StageStream, from, to, model, rawJSON)
+	return fn.Stream(ctx, model, originalRequestRawJSON, requestRawJSON, rawJSON, param)
+}
+
+func runNonStream(ctx context.Context, fn ResponseTransform, from, to Format, model string, originalRequestRawJSON, requestRawJSON, rawJSON []byte, param *any) []byte {
+	defer reportPanic(StageNonStream, from, to, model, rawJSON)
+	return checkOutput(StageNonStream, from, to, model, rawJSON, fn.NonStream(ctx, model, originalRequestRawJSON, requestRawJSON, rawJSON, param))
+}
@@ -73,7 +73,7 @@ func (r *Registry) TranslateRequest(from, to Format, model string, rawJSON []byt
 	if byTarget, ok := r.requests[from]; ok {
 		if fn, isOk := byTarget[to]; isOk && fn != nil {
 			r.mu.RUnlock()
-			return fn(model, rawJSON, stream)
+			return runRequest(fn, from, to, model, rawJSON, stream)
 		}
 	}
 
@@ -105,7 +105,7 @@ func (r *Registry) TranslateRequest(from, to Format, model string, rawJSON []byt
 		if byTarget, ok := r.requests[from]; ok {
 			if fn, isOk := byTarget[to]; isOk && fn != nil {
 				r.mu.RUnlock()
-				return fn(model, rawJSON, stream)
+				return runRequest(fn, from, to, model, rawJSON, stream)
 			}
 		}
 		r.mu.RUnlock()
@@ -146,7 +146,7 @@ func (r *Registry) TranslateStream(ctx context.Context, from, to Format, model s
 	if byTarget, ok := r.responses[to]; ok {
 		if fn, isOk := byTarget[from]; isOk && fn.Stream != nil {
 			r.mu.RUnlock()
-			return fn.Stream(ctx, model, originalRequestRawJSON, requestRawJSON, rawJSON, param)
+			return runStream(ctx, fn, from, to, model, originalRequestRawJSON, requestRawJSON, rawJSON, param)
 		}
 	}
 
@@ -176,7 +176,7 @@ func (r *Registry) TranslateStream(ctx context.Context, from, to Format, model s
 		if byTarget, ok := r.responses[to]; ok {
 			if fn, isOk := byTarget[from]; isOk && fn.Stream != nil {
 				r.mu.RUnlock()
-				return fn.Stream(ctx, model, originalRequestRawJSON, requestRawJSON, rawJSON, param)
+				return runStream(ctx, fn, from, to, model, originalRequestRawJSON, requestRawJSON, rawJSON, param)
 			}
 		}
 		r.mu.RUnlock()
@@ -191,7 +191,7 @@ func (r *Registry) TranslateNonStream(ctx context.Context, from, to Format, mode
 	if byTarget, ok := r.responses[to]; ok {
 		if fn, isOk := byTarget[from]; isOk && fn.NonStream != nil {
 			r.mu.RUnlock()
-			return fn.NonStream(ctx, model, originalRequestRawJSON, requestRawJSON, rawJSON, param)
+			return runNonStream(ctx, fn, from, to, model, originalRequestRawJSON, requestRawJSON, rawJSON, param)
 		}
 	}
 
@@ -221,7 +221,7 @@ func (r *Registry) TranslateNonStream(ctx context.Context, from, to Format, mode
 		if byTarget, ok := r.responses[to]; ok {
 			if fn, isOk := byTarget[from]; isOk && fn.NonStream != nil {
 				r.mu.RUnlock()
-				return fn.NonStream(ctx, model, originalRequestRawJSON, requestRawJSON, rawJSON, param)
+				return runNonStream(ctx, fn, from, to, model, originalRequestRawJSON, requestRawJSON, rawJSON, param)
 			}
 		}
 		r.mu.RUnlock()